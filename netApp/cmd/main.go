@@ -2,35 +2,149 @@ package main
 
 import (
 	"classroomWebGolang/configs"
+	"classroomWebGolang/internal/docs"
+	"classroomWebGolang/internal/health"
+	"classroomWebGolang/internal/openapi"
 	"classroomWebGolang/internal/record"
 	"classroomWebGolang/pkg/db"
-	"log"
+	"classroomWebGolang/pkg/metrics"
+	"classroomWebGolang/pkg/middleware"
+	"classroomWebGolang/pkg/router"
+	"classroomWebGolang/pkg/version"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
 )
 
 func main() {
 	conf := configs.LoadConfig()
 
-	db, err := db.NewDb(conf)
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: conf.Log.Level}))
+	slog.SetDefault(logger)
+
+	err := conf.Validate()
+	if err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := db.NewDbWithRetry(conf, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("connected to database", "driver", conf.Db.Driver, "dsn", conf.Db.Redacted())
+
+	if conf.App.AutoMigrate {
+		start := time.Now()
+		err = record.Migrate(db)
+		if err != nil {
+			logger.Error("failed to migrate database", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("database migrated", "duration", time.Since(start))
+	}
+
+	mux := http.NewServeMux()
+	apiMux := http.NewServeMux()
+
+	appMetrics := metrics.NewMetrics()
+	err = appMetrics.RegisterDb(db)
 	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+		logger.Error("failed to register database metrics", "error", err)
+		os.Exit(1)
 	}
-	log.Println("DB_DSN is %s\n", conf.Db.Dsn)
+	mux.Handle("GET /metrics", appMetrics.Handler())
 
-	router := http.NewServeMux()
+	recordRepository := record.NewRecordRepository(db, logger)
+	recordEvents := record.NewBroker()
 
-	recordRepository := record.NewRecordRepository(db)
+	health.NewHealthHandler(mux, &health.HealthHandlerDeps{Db: db, Config: conf, Logger: logger})
+	docs.NewDocsHandler(mux, conf.App.BasePath)
+	openapi.NewOpenApiHandler(mux, &openapi.OpenApiHandlerDeps{Config: conf, Logger: logger})
+	record.NewRecordHandler(apiMux, &record.RecordHandlerDeps{RecordRepository: recordRepository, Events: recordEvents, Config: conf, Logger: logger})
 
-	record.NewRecordHandler(router, &record.RecordHandlerDeps{RecordRepository: recordRepository, Config: conf})
+	router.Version(mux, conf.App.ApiVersion, metrics.Route(apiMux))
+	deprecated := middleware.Deprecated(conf.App.BasePath + "/" + conf.App.ApiVersion)(apiMux)
+	mux.Handle("/person", deprecated)
+	mux.Handle("/person/", deprecated)
 
+	handler := middleware.Chain(router.Mount(conf.App.BasePath, metrics.Route(mux)),
+		middleware.Recover(logger),
+		middleware.RequestID,
+		middleware.Logging(logger),
+		middleware.Gzip,
+		middleware.Cors(conf.Cors.Origins),
+		middleware.RateLimit(conf.RateLimit.Rps, conf.RateLimit.Burst, conf.Auth.ApiKeys),
+		middleware.DebugSQL(middleware.NewAuth(conf.Auth)),
+		middleware.Timeout(conf.App.HandlerTimeout, "/person/export.csv", "/person/stream"),
+		appMetrics.Middleware,
+	)
+
+	var openConns atomic.Int64
 	server := http.Server{
-		Addr:    ":8000",
-		Handler: router,
+		Addr:              ":" + conf.App.Port,
+		Handler:           handler,
+		ReadTimeout:       conf.App.ReadTimeout,
+		ReadHeaderTimeout: conf.App.ReadHeaderTimeout,
+		WriteTimeout:      conf.App.WriteTimeout,
+		IdleTimeout:       conf.App.IdleTimeout,
+		ConnState: func(conn net.Conn, state http.ConnState) {
+			switch state {
+			case http.StateNew:
+				openConns.Add(1)
+			case http.StateHijacked, http.StateClosed:
+				openConns.Add(-1)
+			}
+		},
 	}
 
-	log.Println("Server is listening on port 8000")
-	err = server.ListenAndServe()
+	// Open event streams never finish on their own, so end them before
+	// Shutdown starts waiting for active connections.
+	server.RegisterOnShutdown(recordEvents.Close)
+
+	go func() {
+		logger.Info("server is listening", "port", conf.App.Port, "commit", version.Commit)
+		err := server.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error("failed to start server", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	<-stop
+	logger.Info("shutting down server")
+
+	exitCode := 0
+	ctx, cancel := context.WithTimeout(context.Background(), conf.App.ShutdownTimeout)
+	err = server.Shutdown(ctx)
+	cancel()
+	if err != nil {
+		logger.Error("failed to shutdown server gracefully",
+			"timeout", conf.App.ShutdownTimeout,
+			"abandoned_connections", openConns.Load(),
+			"error", err,
+		)
+		err = server.Close()
+		if err != nil {
+			logger.Error("failed to close server", "error", err)
+		}
+		exitCode = 1
+	}
+	err = db.Close()
 	if err != nil {
-		log.Fatalf("failed to start server: %v", err)
+		logger.Error("failed to close database connection", "error", err)
+		exitCode = 1
 	}
+	logger.Info("server stopped")
+	os.Exit(exitCode)
 }
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/internal/record"
+	"classroomWebGolang/pkg/db"
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+func main() {
+	count := flag.Int("count", 50, "number of fake records to create")
+	flag.Parse()
+	if *count <= 0 {
+		fmt.Fprintf(os.Stderr, "invalid -count %d: must be a positive number\n", *count)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	conf := configs.LoadConfig()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: conf.Log.Level}))
+
+	if err := conf.Validate(); err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
+	}
+
+	db, err := db.NewDb(conf, logger)
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	recordRepository := record.NewRecordRepository(db, logger)
+
+	records := make([]*record.Record, 0, *count)
+	for range *count {
+		records = append(records, record.NewRecord())
+	}
+	created, err := recordRepository.CreateRecords(context.Background(), records)
+	if err != nil {
+		logger.Error("failed to seed records", "error", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Created %d records\n", created)
+}
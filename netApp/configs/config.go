@@ -1,27 +1,401 @@
 package configs
 
 import (
+	"classroomWebGolang/pkg/phone"
+	"errors"
+	"fmt"
 	"github.com/joho/godotenv"
-	"log"
+	"io/fs"
+	"log/slog"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Config struct {
-	Db DbConfig
+	App        AppConfig        `yaml:"app"`
+	Log        LogConfig        `yaml:"log"`
+	Db         DbConfig         `yaml:"db"`
+	Cors       CorsConfig       `yaml:"cors"`
+	Auth       AuthConfig       `yaml:"auth"`
+	RateLimit  RateLimitConfig  `yaml:"rate_limit"`
+	Validation ValidationConfig `yaml:"validation"`
+	Import     ImportConfig     `yaml:"import"`
+}
+
+type AppConfig struct {
+	Port       string `yaml:"port"`
+	ApiVersion string `yaml:"api_version"`
+	// ShutdownTimeout bounds waiting for in-flight requests on shutdown, defaults to 15s.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	// ReadTimeout bounds reading a whole request, defaults to 15s.
+	ReadTimeout time.Duration `yaml:"read_timeout"`
+	// ReadHeaderTimeout bounds reading request headers, defaults to 5s.
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	// WriteTimeout bounds writing a response, defaults to 15s.
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	// IdleTimeout bounds keep-alive connections between requests, defaults to 60s.
+	IdleTimeout time.Duration `yaml:"idle_timeout"`
+	// MaxBodySize limits JSON request bodies in bytes, defaults to 1 MiB.
+	MaxBodySize int64 `yaml:"max_body_size"`
+	// HandlerTimeout bounds how long a handler may run before it is answered
+	// with 503, defaults to 10s. Zero disables the limit.
+	HandlerTimeout time.Duration `yaml:"handler_timeout"`
+	// IdempotencyKeyTTL is how long an Idempotency-Key replays the record it
+	// created, defaults to 24h.
+	IdempotencyKeyTTL time.Duration `yaml:"idempotency_key_ttl"`
+	// AutoMigrate runs the database migration on startup, defaults to false.
+	AutoMigrate bool `yaml:"auto_migrate"`
+	// BasePath mounts every route under a prefix such as /api, defaults to
+	// empty. It is normalized to a leading slash and no trailing slash.
+	BasePath string `yaml:"base_path"`
+	// ReadOnly answers write routes with 503 during maintenance, defaults
+	// to false.
+	ReadOnly bool `yaml:"read_only"`
+	// MaxGenerateCount caps POST /person/generate, defaults to 1000.
+	MaxGenerateCount int `yaml:"max_generate_count"`
+}
+
+type LogConfig struct {
+	Level slog.Level `yaml:"level"`
 }
 
 type DbConfig struct {
-	Dsn string
+	// Driver selects the database driver: postgres (default) or sqlite.
+	// Use DB_DSN="file::memory:?cache=shared" with sqlite for an in-memory database.
+	Driver       string        `yaml:"driver"`
+	Dsn          string        `yaml:"dsn"`
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// MaxOpenConns limits open connections to the database, defaults to 25.
+	MaxOpenConns int `yaml:"max_open_conns"`
+	// MaxIdleConns limits idle connections kept in the pool, defaults to 5.
+	MaxIdleConns int `yaml:"max_idle_conns"`
+	// ConnMaxLifetime limits how long a connection may be reused, defaults to 5m.
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	// ConnectAttempts limits connection attempts on startup, defaults to 5.
+	ConnectAttempts int `yaml:"connect_attempts"`
+	// ConnectDelay is the initial delay between attempts and doubles each retry, defaults to 1s.
+	ConnectDelay time.Duration `yaml:"connect_delay"`
+	// LogLevel controls SQL logging: silent, error, warn (default) or info.
+	LogLevel string `yaml:"log_level"`
+	// Schema is the Postgres schema tables live in, defaults to public.
+	// It is created by the migration if missing.
+	Schema string `yaml:"schema"`
+	// TimeZone is set on every Postgres session and used for CreatedAt and
+	// UpdatedAt, defaults to UTC. Empty keeps the server and process defaults.
+	TimeZone string `yaml:"timezone"`
+}
+
+type CorsConfig struct {
+	Origins []string `yaml:"origins"`
+}
+
+const (
+	AuthMethodJwt    = "jwt"
+	AuthMethodApiKey = "api_key"
+)
+
+type AuthConfig struct {
+	// Method is either jwt (default) or api_key.
+	Method    string   `yaml:"method"`
+	JwtSecret string   `yaml:"jwt_secret"`
+	ApiKeys   []string `yaml:"api_keys"`
+}
+
+type RateLimitConfig struct {
+	// Rps is the sustained number of requests per second per client, defaults to 10.
+	Rps float64 `yaml:"rps"`
+	// Burst is the number of requests a client may make at once, defaults to 20.
+	Burst int `yaml:"burst"`
+}
+
+type ImportConfig struct {
+	// MaxFileSize limits the size of uploaded CSV files in bytes, defaults to 10 MiB.
+	MaxFileSize int64 `yaml:"max_file_size"`
+}
+
+type ValidationConfig struct {
+	// PhoneFormat is one of ANY (default), E164, US or RU.
+	PhoneFormat string `yaml:"phone_format"`
+	// MaxAge is the highest accepted age, defaults to 150.
+	MaxAge int `yaml:"max_age"`
+	// MinNameLength and MaxNameLength bound the name in characters,
+	// defaulting to 1 and 255.
+	MinNameLength int `yaml:"min_name_length"`
+	MaxNameLength int `yaml:"max_name_length"`
+	// PhoneRequired rejects records without a phone number, defaults to true.
+	PhoneRequired bool `yaml:"phone_required"`
 }
 
 func LoadConfig() *Config {
 	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		fatal("Error loading .env file", "error", err)
+	}
+	file := defaultConfig()
+	path := os.Getenv("CONFIG_FILE")
+	if path != "" {
+		err = loadFile(path, file)
+		if err != nil {
+			fatal("Error loading config file", "path", path, "error", err)
+		}
 	}
 	return &Config{
+		App: AppConfig{
+			Port:              getEnv("APP_PORT", file.App.Port),
+			ApiVersion:        getEnv("API_VERSION", file.App.ApiVersion),
+			ShutdownTimeout:   getEnvDuration("SHUTDOWN_TIMEOUT", file.App.ShutdownTimeout),
+			ReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", file.App.ReadTimeout),
+			ReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", file.App.ReadHeaderTimeout),
+			WriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", file.App.WriteTimeout),
+			IdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", file.App.IdleTimeout),
+			MaxBodySize:       getEnvInt64("MAX_BODY_SIZE", file.App.MaxBodySize),
+			HandlerTimeout:    getEnvDuration("HANDLER_TIMEOUT", file.App.HandlerTimeout),
+			IdempotencyKeyTTL: getEnvDuration("IDEMPOTENCY_KEY_TTL", file.App.IdempotencyKeyTTL),
+			AutoMigrate:       getEnvBool("AUTO_MIGRATE", file.App.AutoMigrate),
+			BasePath:          getEnvBasePath("BASE_PATH", file.App.BasePath),
+			ReadOnly:          getEnvBool("READ_ONLY", file.App.ReadOnly),
+			MaxGenerateCount:  getEnvInt("MAX_GENERATE_COUNT", file.App.MaxGenerateCount),
+		},
+		Log: LogConfig{
+			Level: getEnvLogLevel("LOG_LEVEL", file.Log.Level),
+		},
 		Db: DbConfig{
-			Dsn: os.Getenv("DB_DSN"),
+			Driver:          getEnv("DB_DRIVER", file.Db.Driver),
+			Dsn:             getEnv("DB_DSN", file.Db.Dsn),
+			QueryTimeout:    getEnvDuration("DB_QUERY_TIMEOUT", file.Db.QueryTimeout),
+			MaxOpenConns:    getEnvInt("DB_MAX_OPEN", file.Db.MaxOpenConns),
+			MaxIdleConns:    getEnvInt("DB_MAX_IDLE", file.Db.MaxIdleConns),
+			ConnMaxLifetime: getEnvDuration("DB_CONN_LIFETIME", file.Db.ConnMaxLifetime),
+			ConnectAttempts: getEnvInt("DB_CONNECT_ATTEMPTS", file.Db.ConnectAttempts),
+			ConnectDelay:    getEnvDuration("DB_CONNECT_DELAY", file.Db.ConnectDelay),
+			LogLevel:        getEnvDbLogLevel("DB_LOG_LEVEL", file.Db.LogLevel),
+			Schema:          getEnvIdentifier("DB_SCHEMA", file.Db.Schema),
+			TimeZone:        getEnvTimeZone("DB_TIMEZONE", file.Db.TimeZone),
+		},
+		Cors: CorsConfig{
+			Origins: getEnvList("CORS_ORIGINS", file.Cors.Origins),
+		},
+		Auth: AuthConfig{
+			Method:    getEnv("AUTH_METHOD", file.Auth.Method),
+			JwtSecret: getEnv("JWT_SECRET", file.Auth.JwtSecret),
+			ApiKeys:   getEnvList("API_KEYS", file.Auth.ApiKeys),
+		},
+		RateLimit: RateLimitConfig{
+			Rps:   getEnvFloat("RATE_LIMIT_RPS", file.RateLimit.Rps),
+			Burst: getEnvInt("RATE_LIMIT_BURST", file.RateLimit.Burst),
+		},
+		Validation: ValidationConfig{
+			PhoneFormat:   getEnvPhoneFormat("PHONE_FORMAT", file.Validation.PhoneFormat),
+			MaxAge:        getEnvInt("VALIDATION_MAX_AGE", file.Validation.MaxAge),
+			MinNameLength: getEnvInt("VALIDATION_MIN_NAME_LENGTH", file.Validation.MinNameLength),
+			MaxNameLength: getEnvInt("VALIDATION_MAX_NAME_LENGTH", file.Validation.MaxNameLength),
+			PhoneRequired: getEnvBool("VALIDATION_PHONE_REQUIRED", file.Validation.PhoneRequired),
 		},
+		Import: ImportConfig{
+			MaxFileSize: getEnvInt64("IMPORT_MAX_FILE_SIZE", file.Import.MaxFileSize),
+		},
+	}
+}
+
+func (c *Config) Validate() error {
+	required := []struct {
+		key   string
+		value string
+	}{
+		{"DB_DSN", c.Db.Dsn},
+	}
+	var missing []string
+	for _, r := range required {
+		if r.value == "" {
+			missing = append(missing, r.key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required environment variables: %s", strings.Join(missing, ", "))
+	}
+	// The port is checked here rather than when read, so a bad value from
+	// the config file is caught as well as one from APP_PORT.
+	if port, err := strconv.Atoi(c.App.Port); err != nil || port < 1 || port > 65535 {
+		return fmt.Errorf("APP_PORT must be a number between 1 and 65535, got %q", c.App.Port)
+	}
+	// An empty credential would leave every write answering 401 and, for
+	// jwt, make an empty HMAC key usable.
+	switch c.Auth.Method {
+	case AuthMethodJwt:
+		if c.Auth.JwtSecret == "" {
+			return errors.New("JWT_SECRET is required when AUTH_METHOD is jwt")
+		}
+	case AuthMethodApiKey:
+		if len(c.Auth.ApiKeys) == 0 {
+			return errors.New("API_KEYS is required when AUTH_METHOD is api_key")
+		}
+	default:
+		return fmt.Errorf("AUTH_METHOD must be %s or %s, got %q", AuthMethodJwt, AuthMethodApiKey, c.Auth.Method)
+	}
+	if c.RateLimit.Rps <= 0 {
+		return fmt.Errorf("RATE_LIMIT_RPS must be positive, got %g", c.RateLimit.Rps)
+	}
+	if c.RateLimit.Burst <= 0 {
+		return fmt.Errorf("RATE_LIMIT_BURST must be positive, got %d", c.RateLimit.Burst)
+	}
+	if c.Validation.MaxAge < 0 {
+		return fmt.Errorf("VALIDATION_MAX_AGE must not be negative, got %d", c.Validation.MaxAge)
+	}
+	if c.Validation.MinNameLength < 0 || c.Validation.MinNameLength > c.Validation.MaxNameLength {
+		return fmt.Errorf("VALIDATION_MIN_NAME_LENGTH %d must be between 0 and VALIDATION_MAX_NAME_LENGTH %d", c.Validation.MinNameLength, c.Validation.MaxNameLength)
+	}
+	return nil
+}
+
+func getEnv(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		fatal("Error parsing integer", "key", key, "error", err)
+	}
+	return number
+}
+
+func getEnvInt64(key string, fallback int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
 	}
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		fatal("Error parsing integer", "key", key, "error", err)
+	}
+	return number
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		fatal("Error parsing float", "key", key, "error", err)
+	}
+	return number
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	flag, err := strconv.ParseBool(value)
+	if err != nil {
+		fatal("Error parsing boolean", "key", key, "error", err)
+	}
+	return flag
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		fatal("Error parsing duration", "key", key, "error", err)
+	}
+	return duration
+}
+
+func getEnvList(key string, fallback []string) []string {
+	if os.Getenv(key) == "" {
+		return fallback
+	}
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvBasePath(key string, fallback string) string {
+	value := strings.Trim(getEnv(key, fallback), "/")
+	if value == "" {
+		return ""
+	}
+	if strings.ContainsAny(value, " ?#{}") {
+		fatal("Error parsing base path", "key", key, "value", value)
+	}
+	return "/" + value
+}
+
+func getEnvPhoneFormat(key string, fallback string) string {
+	value := getEnv(key, fallback)
+	if !phone.IsSupportedFormat(value) {
+		fatal("Error parsing phone format", "key", key, "value", value)
+	}
+	return value
+}
+
+func getEnvDbLogLevel(key string, fallback string) string {
+	value := getEnv(key, fallback)
+	switch value {
+	case "silent", "error", "warn", "info":
+		return value
+	}
+	fatal("Error parsing database log level", "key", key, "value", value)
+	return ""
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func getEnvIdentifier(key string, fallback string) string {
+	value := getEnv(key, fallback)
+	if value != "" && !identifierPattern.MatchString(value) {
+		fatal("Error parsing identifier", "key", key, "value", value)
+	}
+	return value
+}
+
+func getEnvTimeZone(key string, fallback string) string {
+	value := getEnv(key, fallback)
+	if value == "" {
+		return value
+	}
+	if _, err := time.LoadLocation(value); err != nil {
+		fatal("Error parsing time zone", "key", key, "error", err)
+	}
+	return value
+}
+
+func getEnvLogLevel(key string, fallback slog.Level) slog.Level {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	var level slog.Level
+	err := level.UnmarshalText([]byte(value))
+	if err != nil {
+		fatal("Error parsing log level", "key", key, "error", err)
+	}
+	return level
+}
+
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
 }
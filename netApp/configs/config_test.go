@@ -0,0 +1,37 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateAuth(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    AuthConfig
+		wantErr string
+	}{
+		{"jwt with secret", AuthConfig{Method: AuthMethodJwt, JwtSecret: "s3cret"}, ""},
+		{"jwt without secret", AuthConfig{Method: AuthMethodJwt}, "JWT_SECRET"},
+		{"api key with keys", AuthConfig{Method: AuthMethodApiKey, ApiKeys: []string{"key"}}, ""},
+		{"api key without keys", AuthConfig{Method: AuthMethodApiKey}, "API_KEYS"},
+		{"unknown method", AuthConfig{Method: "basic", JwtSecret: "s3cret"}, "AUTH_METHOD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := defaultConfig()
+			conf.Db.Dsn = "host=localhost"
+			conf.Auth = tt.auth
+			err := conf.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want an error naming %s", err, tt.wantErr)
+			}
+		})
+	}
+}
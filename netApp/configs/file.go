@@ -0,0 +1,68 @@
+package configs
+
+import (
+	"gopkg.in/yaml.v3"
+	"log/slog"
+	"os"
+	"time"
+)
+
+func defaultConfig() *Config {
+	return &Config{
+		App: AppConfig{
+			Port:              "8000",
+			ApiVersion:        "v1",
+			ShutdownTimeout:   15 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      15 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxBodySize:       1 << 20,
+			HandlerTimeout:    10 * time.Second,
+			IdempotencyKeyTTL: 24 * time.Hour,
+			MaxGenerateCount:  1000,
+		},
+		Log: LogConfig{
+			Level: slog.LevelInfo,
+		},
+		Db: DbConfig{
+			Driver:          "postgres",
+			QueryTimeout:    5 * time.Second,
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnectAttempts: 5,
+			ConnectDelay:    time.Second,
+			LogLevel:        "warn",
+			TimeZone:        "UTC",
+		},
+		Auth: AuthConfig{
+			Method: AuthMethodJwt,
+		},
+		RateLimit: RateLimitConfig{
+			Rps:   10,
+			Burst: 20,
+		},
+		Validation: ValidationConfig{
+			PhoneFormat:   "ANY",
+			MaxAge:        150,
+			MinNameLength: 1,
+			MaxNameLength: 255,
+			PhoneRequired: true,
+		},
+		Import: ImportConfig{
+			MaxFileSize: 10 << 20,
+		},
+	}
+}
+
+func loadFile(path string, conf *Config) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	return decoder.Decode(conf)
+}
@@ -0,0 +1,20 @@
+package configs
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var dsnPassword = regexp.MustCompile(`(?i)(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redacted returns the DSN with any password masked so it is safe to log.
+func (c DbConfig) Redacted() string {
+	if strings.Contains(c.Dsn, "://") {
+		if u, err := url.Parse(c.Dsn); err == nil {
+			return u.Redacted()
+		}
+		return "invalid dsn"
+	}
+	return dsnPassword.ReplaceAllString(c.Dsn, "${1}xxxxx")
+}
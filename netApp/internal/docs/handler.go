@@ -0,0 +1,22 @@
+package docs
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed swagger-ui
+var assets embed.FS
+
+// NewDocsHandler needs basePath because the redirect is resolved against
+// the path after the prefix has been stripped.
+func NewDocsHandler(router *http.ServeMux, basePath string) {
+	swaggerUi, err := fs.Sub(assets, "swagger-ui")
+	if err != nil {
+		panic(err)
+	}
+
+	router.Handle("GET /docs", http.RedirectHandler(basePath+"/docs/", http.StatusMovedPermanently))
+	router.Handle("GET /docs/", http.StripPrefix("/docs/", http.FileServerFS(swaggerUi)))
+}
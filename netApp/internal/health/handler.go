@@ -0,0 +1,103 @@
+package health
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/db"
+	"classroomWebGolang/pkg/middleware"
+	"classroomWebGolang/pkg/response"
+	"classroomWebGolang/pkg/version"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const readyTimeout = 2 * time.Second
+
+type HealthHandlerDeps struct {
+	Db     *db.Db
+	Config *configs.Config
+	Logger *slog.Logger
+}
+
+type HealthHandler struct {
+	Db      *db.Db
+	Logger  *slog.Logger
+	started time.Time
+}
+
+func NewHealthHandler(router *http.ServeMux, deps *HealthHandlerDeps) {
+	handler := &HealthHandler{
+		Db:      deps.Db,
+		Logger:  deps.Logger,
+		started: time.Now(),
+	}
+
+	router.HandleFunc("GET /health", handler.Health())
+	router.Handle("GET /health/detailed", middleware.NewAuth(deps.Config.Auth)(handler.Detailed()))
+	router.HandleFunc("GET /ready", handler.Ready())
+	router.HandleFunc("GET /version", handler.Version())
+}
+
+func (h *HealthHandler) Health() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := response.Json(w, HealthResponse{Status: "ok"}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *HealthHandler) Version() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := response.Json(w, version.Get(), http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *HealthHandler) Ready() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		err := h.Db.Ping(ctx)
+		if err != nil {
+			h.Logger.Warn("database ping failed", "error", err)
+			response.JsonError(w, r, response.CodeUnavailable, http.StatusServiceUnavailable)
+			return
+		}
+		if err := response.Json(w, HealthResponse{Status: "ok"}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *HealthHandler) Detailed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readyTimeout)
+		defer cancel()
+		resp := DetailedHealthResponse{
+			Status: "ok",
+			Uptime: time.Since(h.started).Round(time.Second).String(),
+			Db:     DbStatus{Status: "ok"},
+		}
+		status := http.StatusOK
+		err := h.Db.Ping(ctx)
+		if err != nil {
+			resp.Status = "degraded"
+			resp.Db.Status = "unavailable"
+			resp.Db.Error = err.Error()
+			status = http.StatusServiceUnavailable
+		}
+		stats, err := h.Db.Stats()
+		if err == nil {
+			resp.Db.OpenConnections = stats.OpenConnections
+			resp.Db.InUse = stats.InUse
+			resp.Db.Idle = stats.Idle
+			resp.Db.WaitCount = stats.WaitCount
+			resp.Db.MaxOpen = stats.MaxOpenConnections
+		}
+		if err := response.Json(w, resp, status); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
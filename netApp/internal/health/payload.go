@@ -0,0 +1,21 @@
+package health
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+type DbStatus struct {
+	Status          string `json:"status"`
+	Error           string `json:"error,omitempty"`
+	OpenConnections int    `json:"open_connections"`
+	InUse           int    `json:"in_use"`
+	Idle            int    `json:"idle"`
+	WaitCount       int64  `json:"wait_count"`
+	MaxOpen         int    `json:"max_open"`
+}
+
+type DetailedHealthResponse struct {
+	Status string   `json:"status"`
+	Uptime string   `json:"uptime"`
+	Db     DbStatus `json:"db"`
+}
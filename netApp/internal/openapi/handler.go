@@ -0,0 +1,35 @@
+package openapi
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/response"
+	"log/slog"
+	"net/http"
+)
+
+type OpenApiHandlerDeps struct {
+	Config *configs.Config
+	Logger *slog.Logger
+}
+
+type OpenApiHandler struct {
+	Document *Document
+	Logger   *slog.Logger
+}
+
+func NewOpenApiHandler(router *http.ServeMux, deps *OpenApiHandlerDeps) {
+	handler := &OpenApiHandler{
+		Document: NewDocument(deps.Config.App.BasePath+"/"+deps.Config.App.ApiVersion, deps.Config.Validation),
+		Logger:   deps.Logger,
+	}
+
+	router.HandleFunc("GET /openapi.json", handler.GetDocument())
+}
+
+func (h *OpenApiHandler) GetDocument() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := response.Json(w, h.Document, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
@@ -0,0 +1,642 @@
+package openapi
+
+import "classroomWebGolang/configs"
+
+func NewDocument(serverUrl string, limits configs.ValidationConfig) *Document {
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info: Info{
+			Title:   "classroomWebGolang",
+			Version: "1.0.0",
+		},
+		Servers: []Server{
+			{URL: serverUrl},
+		},
+		Paths: map[string]PathItem{
+			"/person": {
+				"get": {
+					Summary:     "List records",
+					OperationID: "getRecords",
+					Tags:        []string{"person"},
+					Parameters: append(filterParams(),
+						query("limit", "Page size, defaults to 20 and is capped at 100", integer()),
+						query("offset", "Number of records to skip", integer()),
+						query("sort", "Sort field, prefix with - for descending", enum("name", "-name", "age", "-age", "created_at", "-created_at")),
+						fieldsParam(),
+						query("after", "Cursor from next_cursor; switches to keyset pagination by created_at and id, pass empty for the first page", str()),
+						envelopeParam(),
+					),
+					Responses: map[string]*Response{
+						"200": pageResponse("A page of records"),
+						"400": errorResponse("Invalid query parameters"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+				"post": {
+					Summary:     "Create a record",
+					OperationID: "createRecord",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters: []Parameter{
+						query("generate", "Ignore the body and create a record from generated data", enum("true")),
+						{Name: "Idempotency-Key", In: "header", Description: "Return the record created by an earlier request with the same key and body instead of inserting again. Keys are scoped to the authenticated client", Schema: str()},
+					},
+					RequestBody: &RequestBody{Content: jsonContent(ref("CreateRecordRequest"))},
+					Responses: map[string]*Response{
+						"201": {
+							Description: "The created record",
+							Headers: map[string]Header{
+								"Location":            {Description: "URL of the created record", Schema: str()},
+								"Idempotent-Replayed": {Description: "Set to true when the record was created by an earlier request", Schema: str()},
+							},
+							Content: jsonContent(ref("Record")),
+						},
+						"400": errorResponse("Malformed request body"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"422": validationResponse(),
+						"409": errorResponse("Phone number is already in use, or Idempotency-Key was used with a different body"),
+						"413": errorResponse("Request body too large"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/bulk": {
+				"post": {
+					Summary:     "Create records in one transaction",
+					OperationID: "createRecordsBulk",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  jsonContent(array(ref("CreateRecordRequest"))),
+					},
+					Responses: map[string]*Response{
+						"201": {Description: "Number of created records", Content: jsonContent(ref("BulkCreateResponse"))},
+						"400": errorResponse("Malformed request body or batch too large"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"422": validationResponse(),
+						"409": errorResponse("Phone number is already in use"),
+						"413": errorResponse("Request body too large"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/import": {
+				"post": {
+					Summary:     "Import records from a CSV file",
+					OperationID: "importRecords",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					RequestBody: &RequestBody{
+						Required: true,
+						Content: map[string]MediaType{
+							"multipart/form-data": {Schema: &Schema{
+								Type: "object",
+								Properties: map[string]*Schema{
+									"file": {Type: "string", Format: "binary"},
+								},
+								Required: []string{"file"},
+							}},
+						},
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "Import summary", Content: jsonContent(ref("ImportResponse"))},
+						"400": errorResponse("Malformed upload"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"413": errorResponse("File too large"),
+						"409": errorResponse("Phone number is already in use"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/count": {
+				"get": {
+					Summary:     "Count records",
+					OperationID: "countRecords",
+					Tags:        []string{"person"},
+					Parameters:  filterParams(),
+					Responses: map[string]*Response{
+						"200": {Description: "Number of matching records", Content: jsonContent(ref("CountResponse"))},
+						"400": errorResponse("Invalid query parameters"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/stats": {
+				"get": {
+					Summary:     "Aggregate the age of records",
+					OperationID: "getRecordStats",
+					Tags:        []string{"person"},
+					Parameters:  filterParams(),
+					Responses: map[string]*Response{
+						"200": {Description: "Count and age aggregates of matching records", Content: jsonContent(ref("RecordStats"))},
+						"400": errorResponse("Invalid query parameters"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/duplicates": {
+				"get": {
+					Summary:     "Find clusters of likely duplicate records",
+					OperationID: "getDuplicates",
+					Tags:        []string{"person"},
+					Parameters: []Parameter{
+						query("by", "Group by normalized phone_number (default) or case-insensitive name", enum("phone_number", "name")),
+						query("limit", "Maximum number of clusters to return", integer()),
+						query("offset", "Number of clusters to skip", integer()),
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "One page of clusters, largest first", Content: jsonContent(ref("DuplicatesResponse"))},
+						"400": errorResponse("Invalid by"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/export.csv": {
+				"get": {
+					Summary:     "Export records as CSV",
+					OperationID: "exportRecords",
+					Tags:        []string{"person"},
+					Responses: map[string]*Response{
+						"200": {
+							Description: "All records as CSV",
+							Content: map[string]MediaType{
+								"text/csv": {Schema: str()},
+							},
+						},
+					},
+				},
+			},
+			"/person/generate": {
+				"post": {
+					Summary:     "Insert fake records",
+					OperationID: "generateRecords",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters: []Parameter{
+						query("count", "Number of records to generate, at most MAX_GENERATE_COUNT", integer()),
+					},
+					Responses: map[string]*Response{
+						"201": {Description: "Number of created records", Content: jsonContent(ref("BulkCreateResponse"))},
+						"400": errorResponse("Missing, invalid or too large count"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"409": errorResponse("Generated phone number already in use"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/stream": {
+				"get": {
+					Summary:     "Stream created records as server-sent events",
+					OperationID: "streamRecords",
+					Tags:        []string{"person"},
+					Responses: map[string]*Response{
+						"200": {
+							Description: "A record.created event per new record, with keep-alive comments in between",
+							Content: map[string]MediaType{
+								"text/event-stream": {Schema: str()},
+							},
+						},
+					},
+				},
+			},
+			"/person/batch-get": {
+				"post": {
+					Summary:     "Get several records by id",
+					OperationID: "batchGetRecords",
+					Tags:        []string{"person"},
+					RequestBody: &RequestBody{Content: jsonContent(ref("BatchGetRequest"))},
+					Responses: map[string]*Response{
+						"200": {Description: "The records that exist, missing ids are skipped", Content: jsonContent(ref("BatchGetResponse"))},
+						"400": errorResponse("Invalid request body"),
+						"413": errorResponse("Request body too large"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/search": {
+				"post": {
+					Summary:     "Search records",
+					OperationID: "searchRecords",
+					Tags:        []string{"person"},
+					Parameters:  []Parameter{envelopeParam()},
+					RequestBody: &RequestBody{Content: jsonContent(ref("SearchRecordsRequest"))},
+					Responses: map[string]*Response{
+						"200": pageResponse("A page of matching records"),
+						"400": errorResponse("Invalid search query"),
+						"413": errorResponse("Request body too large"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/bulk-update": {
+				"post": {
+					Summary:     "Update all records matching a filter",
+					OperationID: "updateRecordsBulk",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					RequestBody: &RequestBody{Required: true, Content: jsonContent(ref("BulkUpdateRequest"))},
+					Responses: map[string]*Response{
+						"200": {Description: "Number of updated records", Content: jsonContent(ref("BulkUpdateResponse"))},
+						"400": errorResponse("Malformed request body, empty filter or empty updates"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"413": errorResponse("Request body too large"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"422": errorResponse("Invalid field values or the filter matches more than 1000 records"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/{id}": {
+				"get": {
+					Summary:     "Get a record",
+					OperationID: "getRecordById",
+					Tags:        []string{"person"},
+					Parameters: []Parameter{
+						idParam(),
+						fieldsParam(),
+						{Name: "If-None-Match", In: "header", Description: "ETag from a previous response", Schema: str()},
+					},
+					Responses: map[string]*Response{
+						"200": {
+							Description: "The record",
+							Headers: map[string]Header{
+								"ETag":          {Description: "Version of the record", Schema: str()},
+								"Last-Modified": {Description: "Time of the last update, pass it back as If-Unmodified-Since", Schema: str()},
+							},
+							Content: jsonContent(ref("Record")),
+						},
+						"304": {Description: "Record has not changed"},
+						"400": errorResponse("Invalid id or fields"),
+						"404": errorResponse("Record not found"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+				"put": {
+					Summary:     "Update a record",
+					OperationID: "updateRecord",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters: []Parameter{
+						idParam(),
+						unmodifiedSinceParam(),
+					},
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  jsonContent(ref("UpdateRecordRequest")),
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "The updated record", Content: jsonContent(ref("Record"))},
+						"400": errorResponse("Invalid id or malformed request body"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"404": errorResponse("Record not found"),
+						"422": validationResponse(),
+						"409": errorResponse("Phone number is already in use"),
+						"413": errorResponse("Request body too large"),
+						"412": errorResponse("Record was modified after If-Unmodified-Since"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+				"patch": {
+					Summary:     "Partially update a record",
+					OperationID: "patchRecord",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters: []Parameter{
+						idParam(),
+						unmodifiedSinceParam(),
+					},
+					RequestBody: &RequestBody{
+						Required: true,
+						Content:  jsonContent(ref("PatchRecordRequest")),
+					},
+					Responses: map[string]*Response{
+						"200": {Description: "The updated record", Content: jsonContent(ref("Record"))},
+						"400": errorResponse("Invalid id, malformed or empty request body"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"404": errorResponse("Record not found"),
+						"409": errorResponse("Phone number is already in use"),
+						"422": validationResponse(),
+						"413": errorResponse("Request body too large"),
+						"412": errorResponse("Record was modified after If-Unmodified-Since"),
+						"415": errorResponse("Content-Type is not application/json"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+				"delete": {
+					Summary:     "Delete a record",
+					OperationID: "deleteRecord",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters: []Parameter{
+						idParam(),
+						query("hard", "Permanently remove the record instead of soft-deleting it", boolean()),
+					},
+					Responses: map[string]*Response{
+						"204": {Description: "Record deleted"},
+						"400": errorResponse("Invalid id"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"404": errorResponse("Record not found"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/{id}/history": {
+				"get": {
+					Summary:     "List the audit trail of a record",
+					OperationID: "getRecordHistory",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters:  []Parameter{idParam()},
+					Responses: map[string]*Response{
+						"200": {Description: "Audit entries, oldest first", Content: jsonContent(ref("HistoryResponse"))},
+						"400": errorResponse("Invalid id"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"404": errorResponse("Record not found"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+			"/person/{id}/restore": {
+				"post": {
+					Summary:     "Restore a soft-deleted record",
+					OperationID: "restoreRecord",
+					Tags:        []string{"person"},
+					Security:    bearerAuth(),
+					Parameters:  []Parameter{idParam()},
+					Responses: map[string]*Response{
+						"200": {Description: "The restored record", Content: jsonContent(ref("Record"))},
+						"400": errorResponse("Invalid id"),
+						"401": errorResponse("Missing or invalid credentials"),
+						"503": errorResponse("Read-only maintenance mode"),
+						"404": errorResponse("Record not found"),
+						"409": errorResponse("Record is not deleted"),
+						"500": errorResponse("Internal server error"),
+					},
+				},
+			},
+		},
+		Components: Components{
+			SecuritySchemes: map[string]*SecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"apiKeyAuth": {Type: "apiKey", Name: "X-Api-Key", In: "header"},
+			},
+			Schemas: map[string]*Schema{
+				"Record": object(map[string]*Schema{
+					"id":           uuidSchema(),
+					"name":         str(),
+					"age":          integer(),
+					"address":      str(),
+					"phone_number": str(),
+					"created_at":   dateTime(),
+					"updated_at":   dateTime(),
+					"deleted_at":   dateTime(),
+				}),
+				"CreateRecordRequest": object(map[string]*Schema{
+					"name":         nameSchema(limits),
+					"age":          describe(ageSchema(limits), "Generated when omitted"),
+					"address":      describe(str(), "Generated when omitted"),
+					"phone_number": str(),
+				}, "name", "phone_number"),
+				"UpdateRecordRequest": object(map[string]*Schema{
+					"name":         nameSchema(limits),
+					"age":          describe(ageSchema(limits), "Generated when omitted"),
+					"address":      describe(str(), "Generated when omitted"),
+					"phone_number": str(),
+				}, "name", "phone_number"),
+				"PatchRecordRequest": object(map[string]*Schema{
+					"name":         nameSchema(limits),
+					"age":          ageSchema(limits),
+					"address":      {Type: "string", Nullable: true, Description: "Empty string or null clears the address, omit the key to keep it"},
+					"phone_number": str(),
+				}),
+				"GetRecordsResponse": object(map[string]*Schema{
+					"items":       array(ref("Record")),
+					"total":       integer(),
+					"limit":       integer(),
+					"offset":      integer(),
+					"next_cursor": str(),
+				}),
+				"SearchRecordsRequest": object(map[string]*Schema{
+					"name":     {Type: "string", Description: "Case-insensitive name substring"},
+					"address":  {Type: "string", Description: "Case-insensitive address substring"},
+					"min_age":  integer(),
+					"max_age":  integer(),
+					"operator": enum("AND", "OR"),
+					"sort":     enum("name", "-name", "age", "-age", "created_at", "-created_at"),
+					"limit":    integer(),
+					"offset":   integer(),
+				}),
+				"BulkUpdateRequest": object(map[string]*Schema{
+					"filter": object(map[string]*Schema{
+						"name":    {Type: "string", Description: "Case-insensitive name substring"},
+						"min_age": integer(),
+						"max_age": integer(),
+					}),
+					"updates": object(map[string]*Schema{
+						"name":    nameSchema(limits),
+						"age":     ageSchema(limits),
+						"address": str(),
+					}),
+				}, "filter", "updates"),
+				"RecordSnapshot": object(map[string]*Schema{
+					"name":         str(),
+					"age":          integer(),
+					"address":      str(),
+					"phone_number": str(),
+				}),
+				"HistoryEntry": object(map[string]*Schema{
+					"id":         integer(),
+					"action":     enum("update", "delete", "hard_delete", "restore"),
+					"old":        ref("RecordSnapshot"),
+					"new":        ref("RecordSnapshot"),
+					"request_id": str(),
+					"subject":    str(),
+					"created_at": dateTime(),
+				}),
+				"HistoryResponse": object(map[string]*Schema{
+					"items": array(ref("HistoryEntry")),
+				}),
+				"BulkUpdateResponse": object(map[string]*Schema{
+					"updated": integer(),
+				}),
+				"BatchGetRequest": object(map[string]*Schema{
+					"ids": array(uuidSchema()),
+				}, "ids"),
+				"BatchGetResponse": object(map[string]*Schema{
+					"items": array(ref("Record")),
+				}),
+				"CountResponse": object(map[string]*Schema{
+					"count": integer(),
+				}),
+				"DuplicatesResponse": object(map[string]*Schema{
+					"by": enum("phone_number", "name"),
+					"items": array(object(map[string]*Schema{
+						"key":     str(),
+						"count":   integer(),
+						"records": array(ref("Record")),
+					})),
+					"total":  integer(),
+					"limit":  integer(),
+					"offset": integer(),
+				}),
+				"RecordStats": object(map[string]*Schema{
+					"count":   integer(),
+					"avg_age": {Type: "number", Nullable: true},
+					"min_age": {Type: "integer", Nullable: true},
+					"max_age": {Type: "integer", Nullable: true},
+				}),
+				"BulkCreateResponse": object(map[string]*Schema{
+					"created": integer(),
+				}),
+				"ImportResponse": object(map[string]*Schema{
+					"inserted": integer(),
+					"rejected": integer(),
+					"errors": array(object(map[string]*Schema{
+						"row":   integer(),
+						"error": str(),
+					})),
+				}),
+				"Error": object(map[string]*Schema{
+					"error":  str(),
+					"code":   str(),
+					"status": integer(),
+				}),
+				"ValidationError": object(map[string]*Schema{
+					"error":  str(),
+					"code":   str(),
+					"status": integer(),
+					"errors": array(object(map[string]*Schema{
+						"field":   {Type: "string", Description: "Path of the invalid field, e.g. age or [3].phone_number"},
+						"message": str(),
+					})),
+				}),
+			},
+		},
+	}
+}
+
+func bearerAuth() []SecurityRequirement {
+	return []SecurityRequirement{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+}
+
+func ref(name string) *Schema {
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func str() *Schema {
+	return &Schema{Type: "string"}
+}
+
+func boolean() *Schema {
+	return &Schema{Type: "boolean"}
+}
+
+func integer() *Schema {
+	return &Schema{Type: "integer"}
+}
+
+func dateTime() *Schema {
+	return &Schema{Type: "string", Format: "date-time"}
+}
+
+func uuidSchema() *Schema {
+	return &Schema{Type: "string", Format: "uuid"}
+}
+
+func ageSchema(limits configs.ValidationConfig) *Schema {
+	minimum, maximum := 0, limits.MaxAge
+	return &Schema{Type: "integer", Minimum: &minimum, Maximum: &maximum}
+}
+
+func describe(schema *Schema, description string) *Schema {
+	schema.Description = description
+	return schema
+}
+
+func nameSchema(limits configs.ValidationConfig) *Schema {
+	minLength, maxLength := limits.MinNameLength, limits.MaxNameLength
+	return &Schema{Type: "string", MinLength: &minLength, MaxLength: &maxLength}
+}
+
+func enum(values ...string) *Schema {
+	return &Schema{Type: "string", Enum: values}
+}
+
+func array(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+func object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{Type: "object", Properties: properties, Required: required}
+}
+
+func query(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func filterParams() []Parameter {
+	return []Parameter{
+		query("name", "Case-insensitive name substring", str()),
+		query("min_age", "Minimum age, inclusive", integer()),
+		query("max_age", "Maximum age, inclusive", integer()),
+		query("include_deleted", "Include soft-deleted records", boolean()),
+		query("only_deleted", "Return only soft-deleted records", boolean()),
+		query("from", "Created at or after this RFC3339 time", dateTime()),
+		query("to", "Created at or before this RFC3339 time", dateTime()),
+	}
+}
+
+func fieldsParam() Parameter {
+	return query("fields", "Comma-separated fields to return: id, name, age, address, phone_number, created_at, updated_at", str())
+}
+
+func unmodifiedSinceParam() Parameter {
+	return Parameter{Name: "If-Unmodified-Since", In: "header", Description: "Reject the update with 412 if the record changed after this HTTP date", Schema: str()}
+}
+
+func envelopeParam() Parameter {
+	return query("envelope", "Pass false to get a bare array with the page metadata in headers", boolean())
+}
+
+func pageResponse(description string) *Response {
+	return &Response{
+		Description: description,
+		Headers: map[string]Header{
+			"X-Total-Count": {Description: "Total number of matching records", Schema: integer()},
+			"X-Limit":       {Description: "Page size, only with envelope=false", Schema: integer()},
+			"X-Offset":      {Description: "Number of skipped records, only with envelope=false", Schema: integer()},
+			"X-Next-Cursor": {Description: "Cursor for the next page, only with envelope=false and after", Schema: str()},
+		},
+		Content: jsonContent(&Schema{OneOf: []*Schema{ref("GetRecordsResponse"), array(ref("Record"))}}),
+	}
+}
+
+func idParam() Parameter {
+	return Parameter{Name: "id", In: "path", Required: true, Schema: uuidSchema()}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func errorResponse(description string) *Response {
+	return &Response{Description: description, Content: jsonContent(ref("Error"))}
+}
+
+func validationResponse() *Response {
+	return &Response{Description: "Validation failed", Content: jsonContent(ref("ValidationError"))}
+}
@@ -0,0 +1,45 @@
+package record
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/response"
+	"errors"
+)
+
+const maxBulkUpdateSize = 1000
+
+var ErrTooManyMatches = errors.New("filter matches too many records")
+
+// Validate requires at least one filter condition so a request can never
+// match the whole table by omission. Phone numbers are unique and cannot be
+// set on several records at once.
+func (b *BulkUpdateRequest) Validate(cfg configs.ValidationConfig) error {
+	if b.Filter.Name == "" && b.Filter.MinAge == nil && b.Filter.MaxAge == nil {
+		return response.NewError(response.CodeEmptyFilter)
+	}
+	if b.Filter.MinAge != nil && b.Filter.MaxAge != nil && *b.Filter.MinAge > *b.Filter.MaxAge {
+		return response.NewError(response.CodeInvalidRange, "min_age", "max_age")
+	}
+	if b.Updates.present["phone_number"] {
+		return response.NewError(response.CodeImmutableField, "phone_number")
+	}
+	var errs ValidationErrors
+	if b.Updates.Name != nil {
+		if err := validateName(*b.Updates.Name, cfg); err != nil {
+			errs = append(errs, FieldError{Field: "updates.name", Message: err.Error()})
+		}
+	}
+	if b.Updates.Age != nil {
+		if err := validateAge(*b.Updates.Age, cfg); err != nil {
+			errs = append(errs, FieldError{Field: "updates.age", Message: err.Error()})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (b *BulkUpdateRequest) RecordFilter() RecordFilter {
+	return RecordFilter{Name: b.Filter.Name, MinAge: b.Filter.MinAge, MaxAge: b.Filter.MaxAge}
+}
@@ -0,0 +1,78 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"strconv"
+	"strings"
+)
+
+var importColumns = []string{"name", "age", "address", "phonenumber"}
+
+type csvRow struct {
+	Line   int
+	Record *Record
+	Err    error
+}
+
+func readCSVRecords(reader io.Reader) ([]csvRow, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, response.NewError(response.CodeInvalidCsv)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range importColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, response.NewError(response.CodeMissingColumn, name)
+		}
+	}
+	// Line numbers come from the reader rather than a counter, since a
+	// quoted field may span several lines. Only a malformed row is reported
+	// and skipped; any other error, such as a broken upload, repeats on
+	// every Read and ends the import.
+	var rows []csvRow
+	for {
+		fields, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			rows = append(rows, csvRow{Line: parseErr.StartLine, Err: err})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := csvReader.FieldPos(0)
+		value := func(name string) string {
+			index := columns[name]
+			if index >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[index])
+		}
+		age, err := strconv.Atoi(value("age"))
+		if err != nil {
+			rows = append(rows, csvRow{Line: line, Err: fmt.Errorf("invalid age: %q is not an integer", value("age"))})
+			continue
+		}
+		rows = append(rows, csvRow{Line: line, Record: &Record{
+			ID:          uuid.New(),
+			Name:        value("name"),
+			Age:         age,
+			Address:     value("address"),
+			PhoneNumber: value("phonenumber"),
+		}})
+	}
+	return rows, nil
+}
@@ -0,0 +1,60 @@
+package record
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadCSVRecordsLineNumbers(t *testing.T) {
+	input := "name,age,address,phonenumber\n" +
+		"Ann,30,\"Main St 1\nApt 2\",+15550000001\n" +
+		"Bob,x,Side St,+15550000002\n" +
+		"Eve,4\"0,Side St,+15550000003\n" +
+		"Joe,50,Side St,+15550000004\n"
+	rows, err := readCSVRecords(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+
+	want := []struct {
+		line    int
+		wantErr bool
+	}{
+		{line: 2},
+		{line: 4, wantErr: true},
+		{line: 5, wantErr: true},
+		{line: 6},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("got %d rows, want %d", len(rows), len(want))
+	}
+	for i, w := range want {
+		if rows[i].Line != w.line {
+			t.Errorf("row %d: line = %d, want %d", i, rows[i].Line, w.line)
+		}
+		if (rows[i].Err != nil) != w.wantErr {
+			t.Errorf("row %d: err = %v, want error %t", i, rows[i].Err, w.wantErr)
+		}
+	}
+	if got := rows[0].Record.Address; got != "Main St 1\nApt 2" {
+		t.Errorf("address = %q, want the multi-line value", got)
+	}
+}
+
+func TestReadCSVRecordsStopsOnReadError(t *testing.T) {
+	broken := errors.New("connection reset")
+	reader := io.MultiReader(
+		strings.NewReader("name,age,address,phonenumber\nAnn,30,Main St,+15550000001\n"),
+		iotest.ErrReader(broken),
+	)
+	rows, err := readCSVRecords(reader)
+	if !errors.Is(err, broken) {
+		t.Fatalf("err = %v, want %v", err, broken)
+	}
+	if rows != nil {
+		t.Errorf("rows = %v, want none", rows)
+	}
+}
@@ -0,0 +1,46 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"encoding/base64"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = response.NewError(response.CodeInvalidCursor)
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func NewCursor(record *Record) Cursor {
+	return Cursor{CreatedAt: record.CreatedAt, ID: record.ID}
+}
+
+func (c Cursor) Encode() string {
+	raw := c.CreatedAt.Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+	cursor := &Cursor{}
+	cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	cursor.ID, err = uuid.Parse(id)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return cursor, nil
+}
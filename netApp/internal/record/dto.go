@@ -0,0 +1,67 @@
+package record
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+type RecordResponse struct {
+	ID          uuid.UUID  `json:"id" xml:"id"`
+	Name        string     `json:"name" xml:"name"`
+	Age         int        `json:"age" xml:"age"`
+	Address     string     `json:"address" xml:"address"`
+	PhoneNumber string     `json:"phone_number" xml:"phone_number"`
+	CreatedAt   time.Time  `json:"-" xml:"created_at"`
+	UpdatedAt   time.Time  `json:"-" xml:"updated_at"`
+	DeletedAt   *time.Time `json:"-" xml:"deleted_at,omitempty"`
+	fields      []string
+}
+
+// toResponse sets DeletedAt only for soft deleted records, which listings
+// return with include_deleted or only_deleted.
+func toResponse(record *Record) RecordResponse {
+	resp := RecordResponse{
+		ID:          record.ID,
+		Name:        record.Name,
+		Age:         record.Age,
+		Address:     record.Address,
+		PhoneNumber: record.PhoneNumber,
+		CreatedAt:   record.CreatedAt.UTC(),
+		UpdatedAt:   record.UpdatedAt.UTC(),
+	}
+	if record.DeletedAt.Valid {
+		deletedAt := record.DeletedAt.Time.UTC()
+		resp.DeletedAt = &deletedAt
+	}
+	return resp
+}
+
+func toResponses(records []Record) []RecordResponse {
+	resp := make([]RecordResponse, len(records))
+	for i := range records {
+		resp[i] = toResponse(&records[i])
+	}
+	return resp
+}
+
+func (r RecordResponse) MarshalJSON() ([]byte, error) {
+	if len(r.fields) > 0 {
+		return r.marshalFieldsJSON()
+	}
+	type plain RecordResponse
+	out := struct {
+		plain
+		CreatedAt string `json:"created_at"`
+		UpdatedAt string `json:"updated_at"`
+		DeletedAt string `json:"deleted_at,omitempty"`
+	}{
+		plain:     plain(r),
+		CreatedAt: r.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: r.UpdatedAt.Format(time.RFC3339),
+	}
+	if r.DeletedAt != nil {
+		out.DeletedAt = r.DeletedAt.Format(time.RFC3339)
+	}
+	return json.Marshal(out)
+}
@@ -0,0 +1,101 @@
+package record
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func jsonKeys(t *testing.T, v any) []string {
+	t.Helper()
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &object); err != nil {
+		t.Fatalf("unmarshal %s: %v", encoded, err)
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func TestJSONKeys(t *testing.T) {
+	now := time.Now()
+	record := &Record{
+		ID:          uuid.New(),
+		Name:        "Ann",
+		Age:         30,
+		Address:     "Main St 1",
+		PhoneNumber: "+15551234567",
+		Model:       gorm.Model{CreatedAt: now, UpdatedAt: now},
+	}
+	deleted := *record
+	deleted.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	sparse := toResponse(record)
+	sparse.fields = []string{"name", "id"}
+
+	tests := []struct {
+		name string
+		v    any
+		want []string
+	}{
+		{"record", record, []string{"address", "age", "id", "name", "phone_number"}},
+		{"response", toResponse(record), []string{"address", "age", "created_at", "id", "name", "phone_number", "updated_at"}},
+		{"deleted response", toResponse(&deleted), []string{"address", "age", "created_at", "deleted_at", "id", "name", "phone_number", "updated_at"}},
+		{"sparse response", sparse, []string{"id", "name"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonKeys(t, tt.v); !slices.Equal(got, tt.want) {
+				t.Errorf("keys = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseTimestampsAreUTC(t *testing.T) {
+	for _, zone := range []string{"UTC", "Europe/Moscow", "America/New_York"} {
+		t.Run(zone, func(t *testing.T) {
+			if _, err := time.LoadLocation(zone); err != nil {
+				t.Skipf("time zone data unavailable: %v", err)
+			}
+			server := newTestServer(t, map[string]string{"DB_TIMEZONE": zone})
+			created := server.create(t, "Ann", "+15551234567")
+
+			w := server.do(t, http.MethodGet, "/person/"+created.ID.String(), nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			var resp map[string]any
+			decodeBody(t, w, &resp)
+			for _, key := range []string{"created_at", "updated_at"} {
+				raw, _ := resp[key].(string)
+				if !strings.HasSuffix(raw, "Z") {
+					t.Errorf("%s = %q, want a UTC timestamp", key, raw)
+					continue
+				}
+				parsed, err := time.Parse(time.RFC3339, raw)
+				if err != nil {
+					t.Errorf("%s = %q is not RFC3339: %v", key, raw, err)
+					continue
+				}
+				if _, offset := parsed.Zone(); offset != 0 {
+					t.Errorf("%s offset = %ds, want 0", key, offset)
+				}
+			}
+			if got := w.Header().Get("Last-Modified"); !strings.HasSuffix(got, " GMT") {
+				t.Errorf("Last-Modified = %q, want GMT", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"gorm.io/gorm"
+)
+
+// duplicateKeys maps the ?by values of GET /person/duplicates to the SQL
+// expression records are grouped by. On Postgres the phone expression does
+// what phone.Normalize does, keeping a leading plus and the digits, so rows
+// stored before normalization was introduced are grouped with their
+// canonical twins.
+var duplicateKeys = map[string]string{
+	"phone_number": `regexp_replace(phone_number, '^\s*(\+?).*$', '\1') || regexp_replace(phone_number, '[^0-9]', '', 'g')`,
+	"name":         "LOWER(TRIM(name))",
+}
+
+// sqlitePhoneKey stands in on SQLite, which has no regexp_replace. It only
+// strips spaces, dashes, parentheses and dots, so legacy rows with other
+// punctuation such as slashes or tabs are not grouped with their twins.
+const sqlitePhoneKey = "REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(phone_number, ' ', ''), '-', ''), '(', ''), ')', ''), '.', '')"
+
+func duplicateKeyExpression(db *gorm.DB, by string) string {
+	if by == "phone_number" && db.Dialector.Name() == "sqlite" {
+		return sqlitePhoneKey
+	}
+	return duplicateKeys[by]
+}
+
+const defaultDuplicateKey = "phone_number"
+
+func parseDuplicateKey(value string) (string, error) {
+	if value == "" {
+		value = defaultDuplicateKey
+	}
+	if _, ok := duplicateKeys[value]; !ok {
+		return "", response.NewError(response.CodeInvalidChoice, "by", value, "phone_number, name")
+	}
+	return value, nil
+}
+
+type DuplicateCluster struct {
+	Key     string
+	Count   int64
+	Records []Record
+}
+
+type DuplicateClusterResponse struct {
+	Key     string           `json:"key" xml:"key"`
+	Count   int64            `json:"count" xml:"count"`
+	Records []RecordResponse `json:"records" xml:"records>record"`
+}
+
+type DuplicatesResponse struct {
+	By     string                     `json:"by" xml:"by"`
+	Items  []DuplicateClusterResponse `json:"items" xml:"items>cluster"`
+	Total  int64                      `json:"total" xml:"total"`
+	Limit  int                        `json:"limit" xml:"limit"`
+	Offset int                        `json:"offset" xml:"offset"`
+}
+
+func toDuplicateResponses(clusters []DuplicateCluster) []DuplicateClusterResponse {
+	resp := make([]DuplicateClusterResponse, len(clusters))
+	for i, cluster := range clusters {
+		resp[i] = DuplicateClusterResponse{Key: cluster.Key, Count: cluster.Count, Records: toResponses(cluster.Records)}
+	}
+	return resp
+}
@@ -0,0 +1,35 @@
+package record
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestFindDuplicatesGroupsLegacyPhoneFormats(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t, newTestConfig(t, nil))
+	// Raw inserts skip BeforeSave, like rows stored before normalization.
+	now := time.Now()
+	for _, number := range []string{"+1 (555) 123-4567", "+1.555.123.4567", "+15551234567", "+15550000000"} {
+		err := repository.Database.Exec(
+			"INSERT INTO records (id, name, age, address, phone_number, created_at, updated_at) VALUES (?, ?, 0, '', ?, ?, ?)",
+			uuid.New(), "Ann", number, now, now,
+		).Error
+		if err != nil {
+			t.Fatalf("insert %q: %v", number, err)
+		}
+	}
+
+	clusters, total, err := repository.FindDuplicates(ctx, "phone_number", 10, 0)
+	if err != nil {
+		t.Fatalf("find duplicates: %v", err)
+	}
+	if total != 1 || len(clusters) != 1 {
+		t.Fatalf("got %d clusters (total %d), want 1", len(clusters), total)
+	}
+	if clusters[0].Key != "+15551234567" || clusters[0].Count != 3 {
+		t.Errorf("cluster = %q with %d records, want +15551234567 with 3", clusters[0].Key, clusters[0].Count)
+	}
+}
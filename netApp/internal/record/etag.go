@@ -0,0 +1,28 @@
+package record
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// ETag versions one representation of the full record: the sparse field
+// set and the media type are hashed too, so a JSON ETag never validates an
+// XML response or a different field selection.
+func (r *Record) ETag(fields []string, mediaType string) string {
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s|%s|%d|%s|%s|%d|%s|%s", r.ID, r.Name, r.Age, r.Address, r.PhoneNumber, r.UpdatedAt.UnixNano(),
+		strings.Join(fields, ","), mediaType)
+	return `W/"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+}
+
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package record
+
+import "sync"
+
+const subscriberBuffer = 64
+
+// Broker fans newly created records out to stream subscribers in this
+// process. Publishing never blocks: a subscriber whose buffer is full misses
+// the event rather than stalling the request that created the record.
+type Broker struct {
+	mu          sync.Mutex
+	subscribers map[chan RecordResponse]struct{}
+	closed      bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[chan RecordResponse]struct{})}
+}
+
+// Subscribe returns a channel of created records and a function that
+// unsubscribes. The channel is closed on unsubscribe and when the broker
+// is closed.
+func (b *Broker) Subscribe() (<-chan RecordResponse, func()) {
+	ch := make(chan RecordResponse, subscriberBuffer)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	b.subscribers[ch] = struct{}{}
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subscribers[ch]; ok {
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+func (b *Broker) Publish(records ...*Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, record := range records {
+		event := toResponse(record)
+		for ch := range b.subscribers {
+			select {
+			case ch <- event:
+			default:
+			}
+		}
+	}
+}
+
+// Close ends every subscription so streams return and graceful shutdown
+// does not wait on them.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for ch := range b.subscribers {
+		delete(b.subscribers, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,125 @@
+package record
+
+import (
+	"bytes"
+	"classroomWebGolang/pkg/response"
+	"encoding/json"
+	"encoding/xml"
+	"slices"
+	"strings"
+	"time"
+)
+
+// selectableFields maps the fields query names, which double as JSON and
+// XML keys, to their columns.
+var selectableFields = map[string]string{
+	"id":           "id",
+	"name":         "name",
+	"age":          "age",
+	"address":      "address",
+	"phone_number": "phone_number",
+	"created_at":   "created_at",
+	"updated_at":   "updated_at",
+}
+
+func ParseFields(raw string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || slices.Contains(fields, field) {
+			continue
+		}
+		if _, ok := selectableFields[field]; !ok {
+			return nil, response.NewError(response.CodeInvalidField, field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// fieldColumns always includes id and created_at, which keyset
+// pagination needs even when the client did not ask for them, and
+// updated_at for Last-Modified.
+func fieldColumns(fields []string) []string {
+	if len(fields) == 0 {
+		return nil
+	}
+	columns := []string{"id", "created_at", "updated_at"}
+	for _, field := range fields {
+		column := selectableFields[field]
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func withFields(items []RecordResponse, fields []string) []RecordResponse {
+	for i := range items {
+		items[i].fields = fields
+	}
+	return items
+}
+
+func (r RecordResponse) value(field string) any {
+	switch field {
+	case "id":
+		return r.ID
+	case "name":
+		return r.Name
+	case "age":
+		return r.Age
+	case "address":
+		return r.Address
+	case "phone_number":
+		return r.PhoneNumber
+	case "created_at":
+		return r.CreatedAt
+	case "updated_at":
+		return r.UpdatedAt
+	}
+	return nil
+}
+
+func (r RecordResponse) marshalFieldsJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, field := range r.fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		value := r.value(field)
+		if t, ok := value.(time.Time); ok {
+			value = t.Format(time.RFC3339)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(encoded)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (r RecordResponse) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if len(r.fields) == 0 {
+		type plain RecordResponse
+		return e.EncodeElement(plain(r), start)
+	}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, field := range r.fields {
+		if err := e.EncodeElement(r.value(field), xml.StartElement{Name: xml.Name{Local: field}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
@@ -0,0 +1,81 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+)
+
+const defaultSort = "created_at DESC, id DESC"
+
+var sortColumns = map[string]string{
+	"name":       "name",
+	"age":        "age",
+	"created_at": "created_at",
+}
+
+type Filter interface {
+	Apply(db *gorm.DB) *gorm.DB
+}
+
+type RecordFilter struct {
+	Name           string
+	MinAge         *int
+	MaxAge         *int
+	IncludeDeleted bool
+	OnlyDeleted    bool
+	From           *time.Time
+	To             *time.Time
+}
+
+func (f RecordFilter) Apply(db *gorm.DB) *gorm.DB {
+	if f.IncludeDeleted || f.OnlyDeleted {
+		db = db.Unscoped()
+	}
+	if f.OnlyDeleted {
+		db = db.Where("deleted_at IS NOT NULL")
+	}
+	if f.Name != "" {
+		db = db.Where(containsClause(db, "name"), f.Name)
+	}
+	if f.MinAge != nil {
+		db = db.Where("age >= ?", *f.MinAge)
+	}
+	if f.MaxAge != nil {
+		db = db.Where("age <= ?", *f.MaxAge)
+	}
+	switch {
+	case f.From != nil && f.To != nil:
+		db = db.Where("created_at BETWEEN ? AND ?", *f.From, *f.To)
+	case f.From != nil:
+		db = db.Where("created_at >= ?", *f.From)
+	case f.To != nil:
+		db = db.Where("created_at <= ?", *f.To)
+	}
+	return db
+}
+
+func containsClause(db *gorm.DB, column string) string {
+	if db.Dialector.Name() == "sqlite" {
+		return column + " LIKE '%' || ? || '%'"
+	}
+	return column + " ILIKE '%' || ? || '%'"
+}
+
+func ParseSort(raw string) (string, error) {
+	if raw == "" {
+		return defaultSort, nil
+	}
+	direction := "ASC"
+	field := raw
+	if strings.HasPrefix(raw, "-") {
+		direction = "DESC"
+		field = strings.TrimPrefix(raw, "-")
+	}
+	column, ok := sortColumns[field]
+	if !ok {
+		return "", response.NewError(response.CodeInvalidSort, field)
+	}
+	return column + " " + direction, nil
+}
@@ -2,50 +2,957 @@ package record
 
 import (
 	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/db"
+	"classroomWebGolang/pkg/middleware"
+	"classroomWebGolang/pkg/phone"
+	"classroomWebGolang/pkg/request"
 	"classroomWebGolang/pkg/response"
-	"log"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultLimit        = 20
+	maxLimit            = 100
+	maxBulkSize         = 1000
+	maxBatchGetSize     = 100
+	exportFlushInterval = 500
+	streamKeepAlive     = 15 * time.Second
 )
 
 type RecordHandlerDeps struct {
 	RecordRepository *RecordRepository
+	Events           *Broker
 	Config           *configs.Config
+	Logger           *slog.Logger
 }
 
 type RecordHandler struct {
 	RecordRepository *RecordRepository
+	Events           *Broker
 	Config           *configs.Config
+	Logger           *slog.Logger
 }
 
 func NewRecordHandler(router *http.ServeMux, deps *RecordHandlerDeps) {
 	handler := &RecordHandler{
 		RecordRepository: deps.RecordRepository,
+		Events:           deps.Events,
 		Config:           deps.Config,
+		Logger:           deps.Logger,
+	}
+
+	auth := middleware.NewAuth(deps.Config.Auth)
+	readOnly := middleware.ReadOnly(deps.Config.App.ReadOnly)
+	write := func(next http.Handler) http.Handler {
+		return readOnly(auth(next))
+	}
+	limit := middleware.MaxBodyBytes(deps.Config.App.MaxBodySize)
+	jsonBody := func(next http.Handler) http.Handler {
+		return middleware.RequireJSON(limit(next))
 	}
+	importLimit := middleware.MaxBodyBytes(deps.Config.Import.MaxFileSize)
 
-	router.HandleFunc("POST /person", handler.CreateRecord())
+	router.Handle("POST /person", write(jsonBody(handler.CreateRecord())))
+	router.Handle("POST /person/bulk", write(jsonBody(handler.CreateRecordsBulk())))
+	router.Handle("POST /person/bulk-update", write(jsonBody(handler.UpdateRecordsBulk())))
+	router.Handle("POST /person/import", write(importLimit(handler.ImportRecords())))
+	router.Handle("POST /person/generate", write(handler.GenerateRecords()))
+	router.Handle("POST /person/batch-get", jsonBody(handler.BatchGetRecords()))
+	router.Handle("POST /person/search", jsonBody(handler.SearchRecords()))
 	router.HandleFunc("GET /person", handler.GetRecords())
+	router.HandleFunc("GET /person/count", handler.CountRecords())
+	router.HandleFunc("GET /person/stats", handler.GetRecordStats())
+	router.HandleFunc("GET /person/duplicates", handler.GetDuplicates())
+	router.HandleFunc("GET /person/export.csv", handler.ExportRecords())
+	router.HandleFunc("GET /person/stream", handler.StreamRecords())
+	router.HandleFunc("GET /person/{id}", handler.GetRecordById())
+	router.Handle("GET /person/{id}/history", auth(handler.GetRecordHistory()))
+	router.Handle("PUT /person/{id}", write(jsonBody(handler.UpdateRecord())))
+	router.Handle("PATCH /person/{id}", write(jsonBody(handler.PatchRecord())))
+	router.Handle("DELETE /person/{id}", write(handler.DeleteRecord()))
+	router.Handle("POST /person/{id}/restore", write(handler.RestoreRecord()))
 }
 
 func (h *RecordHandler) CreateRecord() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		log.Println("CreateRecord")
-		record := NewRecord()
-		createRecord, err := h.RecordRepository.CreateRecord(record)
+		var record *Record
+		// payload identifies the request for Idempotency-Key reuse checks;
+		// generated records differ on every call, so only the mode counts.
+		var payload any = "generate"
+		if r.URL.Query().Get("generate") == "true" {
+			record = NewRecord()
+			if err := h.validateRecord(record); err != nil {
+				h.writeValidationError(w, r, err)
+				return
+			}
+		} else {
+			body, err := request.HandleBody[CreateRecordRequest](&w, r)
+			if err != nil {
+				return
+			}
+			record, err = h.recordFromRequest(body)
+			if err != nil {
+				h.writeValidationError(w, r, err)
+				return
+			}
+			payload = body
+		}
+		key := r.Header.Get("Idempotency-Key")
+		if len(key) > maxIdempotencyKeyLength {
+			response.JsonError(w, r, response.CodeIdempotencyKeyTooLong, http.StatusBadRequest, maxIdempotencyKeyLength)
+			return
+		}
+		var createRecord *Record
+		var replayed bool
+		var err error
+		if key == "" {
+			createRecord, err = h.RecordRepository.CreateRecord(r.Context(), record)
+		} else {
+			var idempotencyKey IdempotencyKey
+			idempotencyKey, err = NewIdempotencyKey(middleware.SubjectFromContext(r.Context()), key, payload)
+			if err == nil {
+				createRecord, replayed, err = h.RecordRepository.CreateRecordIdempotent(r.Context(), record, idempotencyKey, h.Config.App.IdempotencyKeyTTL)
+			}
+		}
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			h.writeError(w, r, err)
+			return
+		}
+		if replayed {
+			w.Header().Set("Idempotent-Replayed", "true")
+		} else {
+			h.Events.Publish(createRecord)
+		}
+		w.Header().Set("Location", h.recordLocation(createRecord))
+		if err := response.Write(w, r, toResponse(createRecord), http.StatusCreated); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) CreateRecordsBulk() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := request.Decode[[]CreateRecordRequest](r.Body)
+		if err != nil {
+			request.WriteDecodeError(w, r, err)
+			return
+		}
+		if len(body) == 0 {
+			response.JsonError(w, r, response.CodeEmptyBatch, http.StatusBadRequest)
+			return
+		}
+		if len(body) > maxBulkSize {
+			response.JsonError(w, r, response.CodeBatchTooLarge, http.StatusBadRequest, len(body), maxBulkSize)
+			return
+		}
+		records := make([]*Record, 0, len(body))
+		var errs ValidationErrors
+		for i := range body {
+			record, err := h.recordFromRequest(&body[i])
+			if err != nil {
+				var recordErrs ValidationErrors
+				if !errors.As(err, &recordErrs) {
+					h.writeValidationError(w, r, err)
+					return
+				}
+				errs = append(errs, recordErrs.WithPrefix(fmt.Sprintf("[%d].", i))...)
+				continue
+			}
+			records = append(records, record)
+		}
+		if len(errs) > 0 {
+			h.writeValidationError(w, r, errs)
+			return
+		}
+		created, err := h.RecordRepository.CreateRecords(r.Context(), records)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.Events.Publish(records...)
+		if err := response.Write(w, r, BulkCreateResponse{Created: created}, http.StatusCreated); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) UpdateRecordsBulk() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := request.HandleBody[BulkUpdateRequest](&w, r)
+		if err != nil {
+			return
+		}
+		if err := body.Validate(h.Config.Validation); err != nil {
+			var validationErrs ValidationErrors
+			if errors.As(err, &validationErrs) {
+				h.writeValidationError(w, r, err)
+				return
+			}
+			h.writeError(w, r, err)
+			return
+		}
+		data := &Record{}
+		fields, err := body.Updates.Apply(data)
+		var validationErrs ValidationErrors
+		if errors.As(err, &validationErrs) {
+			h.writeValidationError(w, r, validationErrs.WithPrefix("updates."))
+			return
+		}
+		if len(fields) == 0 {
+			response.JsonError(w, r, response.CodeEmptyPatch, http.StatusBadRequest)
+			return
+		}
+		updated, err := h.RecordRepository.UpdateRecordsByFilter(r.Context(), body.RecordFilter(), fields, data, maxBulkUpdateSize)
+		if errors.Is(err, ErrTooManyMatches) {
+			response.JsonError(w, r, response.CodeTooManyMatches, http.StatusUnprocessableEntity, updated, maxBulkUpdateSize)
+			return
+		}
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.Logger.Info("records bulk updated",
+			"updated", updated,
+			"fields", fields,
+			"subject", middleware.SubjectFromContext(r.Context()),
+			"request_id", middleware.RequestIDFromContext(r.Context()),
+		)
+		if err := response.Write(w, r, BulkUpdateResponse{Updated: updated}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) ImportRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				response.JsonError(w, r, response.CodeFileTooLarge, http.StatusRequestEntityTooLarge, maxBytesErr.Limit)
+				return
+			}
+			response.JsonError(w, r, response.CodeMissingFile, http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		rows, err := readCSVRecords(file)
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			response.JsonError(w, r, response.CodeFileTooLarge, http.StatusRequestEntityTooLarge, maxBytesErr.Limit)
+			return
+		}
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		resp := ImportResponse{Errors: []ImportRowError{}}
+		var valid []csvRow
+		var phones []string
+		for _, row := range rows {
+			err := row.Err
+			if err == nil {
+				err = h.validateRecord(row.Record)
+			}
+			if err != nil {
+				resp.Errors = append(resp.Errors, ImportRowError{Row: row.Line, Error: err.Error()})
+				continue
+			}
+			valid = append(valid, row)
+			phones = append(phones, phone.Normalize(row.Record.PhoneNumber))
+		}
+		// Phone conflicts are rejected per row up front, so one taken number
+		// does not fail the whole import with 409.
+		taken, err := h.RecordRepository.ExistingPhoneNumbers(r.Context(), phones)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		seen := make(map[string]int, len(valid))
+		var records []*Record
+		for i, row := range valid {
+			number := phones[i]
+			if taken[number] {
+				resp.Errors = append(resp.Errors, ImportRowError{Row: row.Line, Error: "phone_number " + number + " is already in use"})
+				continue
+			}
+			if first, ok := seen[number]; ok && number != "" {
+				resp.Errors = append(resp.Errors, ImportRowError{Row: row.Line, Error: fmt.Sprintf("phone_number %s duplicates row %d", number, first)})
+				continue
+			}
+			seen[number] = row.Line
+			records = append(records, row.Record)
+		}
+		slices.SortFunc(resp.Errors, func(a, b ImportRowError) int {
+			return a.Row - b.Row
+		})
+		if len(records) > 0 {
+			resp.Inserted, err = h.RecordRepository.CreateRecords(r.Context(), records)
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+			h.Events.Publish(records...)
+		}
+		resp.Rejected = len(resp.Errors)
+		if err := response.Write(w, r, resp, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+// GenerateRecords inserts count fake records for demos and load tests. They
+// skip validation, since faker output need not match the configured phone
+// format or limits.
+func (h *RecordHandler) GenerateRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		raw := r.URL.Query().Get("count")
+		count, err := strconv.Atoi(raw)
+		if err != nil || count <= 0 {
+			response.JsonError(w, r, response.CodeInvalidCount, http.StatusBadRequest, raw)
+			return
+		}
+		if count > h.Config.App.MaxGenerateCount {
+			response.JsonError(w, r, response.CodeBatchTooLarge, http.StatusBadRequest, count, h.Config.App.MaxGenerateCount)
+			return
+		}
+		records := make([]*Record, count)
+		for i := range records {
+			records[i] = NewRecord()
+		}
+		created, err := h.RecordRepository.CreateRecords(r.Context(), records)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.Events.Publish(records...)
+		if err := response.Write(w, r, BulkCreateResponse{Created: created}, http.StatusCreated); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
 		}
-		response.Json(w, createRecord, http.StatusCreated)
 	}
 }
 
 func (h *RecordHandler) GetRecords() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		log.Println("GetRecords")
-		records, err := h.RecordRepository.GetRecords()
+		limit, offset := parsePagination(r)
+		filter, err := parseFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		fields, err := ParseFields(r.URL.Query().Get("fields"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		mode, err := parsePaginationMode(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if r.URL.Query().Has("after") {
+			h.getRecordsAfter(w, r, filter, fields, limit, mode)
+			return
+		}
+		order, err := ParseSort(r.URL.Query().Get("sort"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		records, err := h.RecordRepository.GetRecords(r.Context(), filter, order, limit, offset, fieldColumns(fields)...)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		total, err := h.RecordRepository.CountRecords(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writePage(w, r, GetRecordsResponse{
+			Items:  withFields(toResponses(records), fields),
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}, mode)
+	}
+}
+
+func (h *RecordHandler) getRecordsAfter(w http.ResponseWriter, r *http.Request, filter RecordFilter, fields []string, limit int, mode PaginationMode) {
+	if r.URL.Query().Get("sort") != "" || r.URL.Query().Get("offset") != "" {
+		response.JsonError(w, r, response.CodeCursorConflict, http.StatusBadRequest)
+		return
+	}
+	var cursor *Cursor
+	if after := r.URL.Query().Get("after"); after != "" {
+		var err error
+		cursor, err = DecodeCursor(after)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+	}
+	records, err := h.RecordRepository.GetRecordsAfter(r.Context(), filter, cursor, limit+1, fieldColumns(fields)...)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	total, err := h.RecordRepository.CountRecords(r.Context(), filter)
+	if err != nil {
+		h.writeError(w, r, err)
+		return
+	}
+	resp := GetRecordsResponse{
+		Total: total,
+		Limit: limit,
+	}
+	if len(records) > limit {
+		records = records[:limit]
+		resp.NextCursor = NewCursor(&records[limit-1]).Encode()
+	}
+	resp.Items = withFields(toResponses(records), fields)
+	h.writePage(w, r, resp, mode)
+}
+
+func (h *RecordHandler) BatchGetRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := request.HandleBody[BatchGetRequest](&w, r)
+		if err != nil {
+			return
+		}
+		if len(body.IDs) == 0 {
+			response.JsonError(w, r, response.CodeEmptyIds, http.StatusBadRequest)
+			return
+		}
+		if len(body.IDs) > maxBatchGetSize {
+			response.JsonError(w, r, response.CodeTooManyIds, http.StatusBadRequest, len(body.IDs), maxBatchGetSize)
+			return
+		}
+		records, err := h.RecordRepository.GetRecordsByIds(r.Context(), body.IDs)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if err := response.Write(w, r, BatchGetResponse{Items: toResponses(records)}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) SearchRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		mode, err := parsePaginationMode(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		body, err := request.HandleBody[SearchRecordsRequest](&w, r)
+		if err != nil {
+			return
+		}
+		if err := body.Validate(); err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		order, err := ParseSort(body.Sort)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		records, err := h.RecordRepository.GetRecords(r.Context(), body, order, body.Limit, body.Offset)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		total, err := h.RecordRepository.CountRecords(r.Context(), body)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		h.writePage(w, r, GetRecordsResponse{
+			Items:  toResponses(records),
+			Total:  total,
+			Limit:  body.Limit,
+			Offset: body.Offset,
+		}, mode)
+	}
+}
+
+func (h *RecordHandler) CountRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		count, err := h.RecordRepository.CountRecords(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if err := response.Write(w, r, CountResponse{Count: count}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) GetRecordStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := parseFilter(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		stats, err := h.RecordRepository.GetRecordStats(r.Context(), filter)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if err := response.Write(w, r, stats, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) GetDuplicates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		by, err := parseDuplicateKey(r.URL.Query().Get("by"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		limit, offset := parsePagination(r)
+		clusters, total, err := h.RecordRepository.FindDuplicates(r.Context(), by, limit, offset)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		resp := DuplicatesResponse{
+			By:     by,
+			Items:  toDuplicateResponses(clusters),
+			Total:  total,
+			Limit:  limit,
+			Offset: offset,
+		}
+		if err := response.Write(w, r, resp, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) ExportRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Large exports outlast the server write timeout.
+		if err := http.NewResponseController(w).SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			h.Logger.Error("failed to clear write deadline", "error", err)
+		}
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", "attachment; filename=persons.csv")
+		writer := csv.NewWriter(w)
+		err := writer.Write([]string{"ID", "Name", "Age", "Address", "PhoneNumber", "CreatedAt"})
+		if err != nil {
+			h.Logger.Error("failed to write csv header", "error", err)
+			return
+		}
+		ctx := r.Context()
+		exported := 0
+		err = h.RecordRepository.EachRecord(ctx, func(record *Record) error {
+			err := writer.Write([]string{
+				record.ID.String(),
+				record.Name,
+				strconv.Itoa(record.Age),
+				record.Address,
+				record.PhoneNumber,
+				record.CreatedAt.UTC().Format(time.RFC3339),
+			})
+			if err != nil {
+				return err
+			}
+			exported++
+			if exported%exportFlushInterval == 0 {
+				writer.Flush()
+				return writer.Error()
+			}
+			return nil
+		})
+		// A disconnect surfaces either as the canceled context or as a
+		// failed write, depending on which is noticed first.
+		if err != nil && ctx.Err() != nil {
+			h.Logger.Info("export aborted by client", "exported", exported, "error", err)
+			return
+		}
+		if err != nil {
+			h.Logger.Error("failed to export records", "error", err)
+			return
+		}
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			h.Logger.Error("failed to flush csv", "error", err)
+		}
+	}
+}
+
+// StreamRecords pushes every record created in this process as a
+// server-sent event until the client disconnects or the server shuts down.
+// Comments are sent in between so idle proxies keep the connection open.
+func (h *RecordHandler) StreamRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		controller := http.NewResponseController(w)
+		// The server write timeout would otherwise cut every stream off.
+		if err := controller.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			h.Logger.Error("failed to clear write deadline", "error", err)
+		}
+		events, unsubscribe := h.Events.Subscribe()
+		defer unsubscribe()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		w.WriteHeader(http.StatusOK)
+		if err := controller.Flush(); err != nil {
+			h.Logger.Error("failed to flush stream", "error", err)
+			return
+		}
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			var err error
+			select {
+			case <-r.Context().Done():
+				return
+			case <-keepAlive.C:
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				err = writeEvent(w, "record.created", event.ID.String(), event)
+			}
+			if err == nil {
+				err = controller.Flush()
+			}
+			if err != nil {
+				h.Logger.Info("stream closed", "error", err)
+				return
+			}
+		}
+	}
+}
+
+func writeEvent(w io.Writer, event, id string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\nid: %s\ndata: %s\n\n", event, id, payload)
+	return err
+}
+
+func (h *RecordHandler) GetRecordById() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		fields, err := ParseFields(r.URL.Query().Get("fields"))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		// The full row is loaded even for sparse fields, since the ETag and
+		// Last-Modified must cover every column.
+		record, err := h.RecordRepository.GetRecordById(r.Context(), id)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		etag := record.ETag(fields, response.MediaType(r))
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", record.UpdatedAt.UTC().Format(http.TimeFormat))
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			// The validators depend on the negotiated type, so caches must
+			// key the 304 on Accept as well.
+			w.Header().Add("Vary", "Accept")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		resp := toResponse(record)
+		resp.fields = fields
+		if err := response.Write(w, r, resp, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+// GetRecordHistory answers 404 only when there is neither history nor a
+// record, so the trail of a hard deleted record stays readable.
+func (h *RecordHandler) GetRecordHistory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		entries, err := h.RecordRepository.GetRecordHistory(r.Context(), id)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if len(entries) == 0 && !h.requireRecord(w, r, id, true) {
+			return
+		}
+		items := make([]HistoryEntryResponse, len(entries))
+		for i := range entries {
+			items[i], err = toHistoryResponse(&entries[i])
+			if err != nil {
+				h.writeError(w, r, err)
+				return
+			}
+		}
+		if err := response.Write(w, r, HistoryResponse{Items: items}, http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) UpdateRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if !h.requireRecord(w, r, id, false) {
+			return
+		}
+		body, err := request.HandleBody[UpdateRecordRequest](&w, r)
+		if err != nil {
+			return
+		}
+		data := &Record{
+			Name:        body.Name,
+			Age:         body.Age,
+			Address:     body.Address,
+			PhoneNumber: body.PhoneNumber,
+		}
+		if err := h.validateRecord(data); err != nil {
+			h.writeValidationError(w, r, err)
+			return
+		}
+		record, err := h.RecordRepository.UpdateRecord(r.Context(), id, data, parseUnmodifiedSince(r))
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if err := response.Write(w, r, toResponse(record), http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) PatchRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		body, err := request.HandleBody[PatchRecordRequest](&w, r)
+		if err != nil {
+			return
+		}
+		existing, err := h.RecordRepository.GetRecordById(r.Context(), id)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		fields, err := body.Apply(existing)
+		if err != nil {
+			h.writeValidationError(w, r, err)
+			return
+		}
+		if len(fields) == 0 {
+			response.JsonError(w, r, response.CodeEmptyPatch, http.StatusBadRequest)
+			return
+		}
+		if err := h.validateRecord(existing); err != nil {
+			h.writeValidationError(w, r, err)
+			return
+		}
+		record, err := h.RecordRepository.PatchRecord(r.Context(), id, fields, existing, parseUnmodifiedSince(r))
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			h.writeError(w, r, err)
+			return
 		}
-		response.Json(w, records, http.StatusOK)
+		if err := response.Write(w, r, toResponse(record), http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+func (h *RecordHandler) DeleteRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		hard, err := parseOptionalBool(r, "hard")
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if !h.requireRecord(w, r, id, hard) {
+			return
+		}
+		if hard {
+			err = h.RecordRepository.HardDeleteRecord(r.Context(), id)
+		} else {
+			err = h.RecordRepository.DeleteRecord(r.Context(), id)
+		}
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if hard {
+			h.Logger.Info("record hard deleted",
+				"id", id,
+				"subject", middleware.SubjectFromContext(r.Context()),
+				"request_id", middleware.RequestIDFromContext(r.Context()),
+			)
+		}
+		response.NoContent(w)
+	}
+}
+
+func (h *RecordHandler) RestoreRecord() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := ParseIDParam(r)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		record, err := h.RecordRepository.RestoreRecord(r.Context(), id)
+		if err != nil {
+			h.writeError(w, r, err)
+			return
+		}
+		if err := response.Write(w, r, toResponse(record), http.StatusOK); err != nil {
+			h.Logger.Error("failed to encode response", "error", err)
+		}
+	}
+}
+
+// requireRecord answers 404 and returns false when the record is missing,
+// so writes fail before touching the row. The write still handles
+// gorm.ErrRecordNotFound for records deleted in between.
+func (h *RecordHandler) requireRecord(w http.ResponseWriter, r *http.Request, id uuid.UUID, includeDeleted bool) bool {
+	exists, err := h.RecordRepository.Exists(r.Context(), id, includeDeleted)
+	if err != nil {
+		h.writeError(w, r, err)
+		return false
+	}
+	if !exists {
+		response.JsonError(w, r, response.CodeNotFound, http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// writeError answers with the catalog code for err. Coded errors come from
+// request parsing and are client mistakes; anything unrecognised is logged
+// and hidden behind a generic 500 so driver messages never leak.
+func (h *RecordHandler) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var codedErr *response.CodedError
+	var idErr *InvalidIDError
+	switch {
+	case errors.As(err, &codedErr):
+		response.JsonError(w, r, codedErr.Code, http.StatusBadRequest, codedErr.Args...)
+	case errors.As(err, &idErr):
+		response.JsonError(w, r, response.CodeInvalidId, http.StatusBadRequest, idErr.Value)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		response.JsonError(w, r, response.CodeNotFound, http.StatusNotFound)
+	case errors.Is(err, ErrRecordModified):
+		response.JsonError(w, r, response.CodeRecordModified, http.StatusPreconditionFailed)
+	case errors.Is(err, ErrRecordNotDeleted):
+		response.JsonError(w, r, response.CodeRecordNotDeleted, http.StatusConflict)
+	case errors.Is(err, ErrIdempotencyKeyReused):
+		response.JsonError(w, r, response.CodeIdempotencyKeyReused, http.StatusConflict)
+	case db.IsUniqueViolation(err):
+		response.JsonError(w, r, response.CodePhoneInUse, http.StatusConflict)
+	default:
+		h.Logger.Error("request failed",
+			"error", err,
+			"request_id", middleware.RequestIDFromContext(r.Context()),
+		)
+		response.JsonError(w, r, response.CodeInternal, http.StatusInternalServerError)
+	}
+}
+
+func (h *RecordHandler) recordLocation(record *Record) string {
+	return h.Config.App.BasePath + "/" + h.Config.App.ApiVersion + "/person/" + record.ID.String()
+}
+
+// recordFromRequest rejects missing required fields before building and
+// validating the record, so nothing is filled in with generated data.
+func (h *RecordHandler) recordFromRequest(body *CreateRecordRequest) (*Record, error) {
+	if err := body.Validate(h.Config.Validation); err != nil {
+		return nil, err
+	}
+	record := NewRecordFromRequest(body)
+	if err := h.validateRecord(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func (h *RecordHandler) validateRecord(record *Record) error {
+	var errs ValidationErrors
+	if err := record.Validate(h.Config.Validation); err != nil {
+		if !errors.As(err, &errs) {
+			return err
+		}
+	}
+	if record.PhoneNumber != "" {
+		if err := phone.Validate(record.PhoneNumber, h.Config.Validation.PhoneFormat); err != nil {
+			errs = append(errs, FieldError{Field: "phone_number", Message: "must match " + h.Config.Validation.PhoneFormat + " format"})
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func (h *RecordHandler) writeValidationError(w http.ResponseWriter, r *http.Request, err error) {
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		h.writeError(w, r, err)
+		return
+	}
+	lang := response.Language(r)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	resp := ValidationErrorResponse{
+		Error:  response.Message(lang, response.CodeValidationFailed),
+		Code:   response.CodeValidationFailed,
+		Status: http.StatusUnprocessableEntity,
+		Errors: errs,
+	}
+	if err := response.Json(w, resp, http.StatusUnprocessableEntity); err != nil {
+		h.Logger.Error("failed to encode response", "error", err)
 	}
 }
@@ -0,0 +1,128 @@
+package record
+
+import (
+	"bytes"
+	"classroomWebGolang/pkg/response"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRepositoryErrorWritesOneResponse(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   any
+	}{
+		{"create", http.MethodPost, "/person", map[string]any{"name": "Ann", "phone_number": "+15551234567"}},
+		{"list", http.MethodGet, "/person", nil},
+		{"get", http.MethodGet, "/person/0b7d5a4e-6a53-4c43-9a36-4d1f2e0f8a10", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			if err := server.repository.Database.Close(); err != nil {
+				t.Fatalf("failed to close database: %v", err)
+			}
+
+			w := server.do(t, tt.method, tt.target, tt.body)
+
+			if w.Code != http.StatusInternalServerError {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+			}
+			if w.headers != 1 {
+				t.Errorf("WriteHeader called %d times, want 1", w.headers)
+			}
+			var resp response.ErrorResponse
+			decodeBody(t, w, &resp)
+			if resp.Code != response.CodeInternal {
+				t.Errorf("code = %q, want %q", resp.Code, response.CodeInternal)
+			}
+		})
+	}
+}
+
+// cancelingWriter cancels the request once the first chunk reaches the
+// client, which is what a disconnect mid-download looks like to a handler.
+type cancelingWriter struct {
+	*httptest.ResponseRecorder
+	cancel context.CancelFunc
+}
+
+func (w *cancelingWriter) Write(b []byte) (int, error) {
+	w.cancel()
+	return w.ResponseRecorder.Write(b)
+}
+
+func TestExportRecordsStopsWhenClientGoesAway(t *testing.T) {
+	server := newTestServer(t, nil)
+	const total = 2 * exportFlushInterval
+	records := make([]*Record, total)
+	for i := range records {
+		records[i] = &Record{ID: uuid.New(), Name: "Ann", PhoneNumber: fmt.Sprintf("+1555%07d", i)}
+	}
+	if _, err := server.repository.CreateRecords(context.Background(), records); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := httptest.NewRequest(http.MethodGet, "/person/export.csv", nil).WithContext(ctx)
+	w := &cancelingWriter{ResponseRecorder: httptest.NewRecorder(), cancel: cancel}
+	server.router.ServeHTTP(w, r)
+
+	lines := bytes.Count(w.Body.Bytes(), []byte("\n"))
+	if lines == 0 {
+		t.Fatal("nothing was written before the cancellation")
+	}
+	if lines >= total+1 {
+		t.Errorf("wrote %d lines, want the export to stop before all %d rows", lines, total)
+	}
+}
+
+func TestUnknownFieldsAreRejected(t *testing.T) {
+	server := newTestServer(t, nil)
+	existing := server.create(t, "Ann", "+15551234567")
+	target := "/person/" + existing.ID.String()
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+	}{
+		{"create", http.MethodPost, "/person", `{"nmae":"Bob","phone_number":"+15557654321"}`},
+		{"bulk create", http.MethodPost, "/person/bulk", `[{"name":"Bob","phone_number":"+15557654321","nmae":"Bob"}]`},
+		{"update", http.MethodPut, target, `{"name":"Bob","age":1,"address":"","phone_number":"+15557654321","nmae":"Bob"}`},
+		{"patch", http.MethodPatch, target, `{"nmae":"Bob"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := server.do(t, tt.method, tt.target, tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+			var resp response.ErrorResponse
+			decodeBody(t, w, &resp)
+			if resp.Code != response.CodeUnknownField {
+				t.Errorf("code = %q, want %q", resp.Code, response.CodeUnknownField)
+			}
+			if !strings.Contains(resp.Error, `"nmae"`) {
+				t.Errorf("error = %q, want it to name the field", resp.Error)
+			}
+		})
+	}
+
+	stored, err := server.repository.GetRecordById(context.Background(), existing.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.Name != "Ann" {
+		t.Errorf("name = %q, want the rejected requests to leave it unchanged", stored.Name)
+	}
+}
@@ -0,0 +1,139 @@
+package record
+
+import (
+	"bytes"
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/db"
+	"classroomWebGolang/pkg/middleware"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+const testApiKey = "test-key"
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// newTestConfig points the config at a fresh sqlite file and switches auth
+// to a fixed API key, so tests need neither Postgres nor a JWT. Syncing is
+// off since the file is thrown away anyway.
+func newTestConfig(t testing.TB, env map[string]string) *configs.Config {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("DB_DSN", filepath.Join(t.TempDir(), "test.db")+"?_pragma=synchronous(off)")
+	t.Setenv("AUTH_METHOD", configs.AuthMethodApiKey)
+	t.Setenv("API_KEYS", testApiKey)
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	return configs.LoadConfig()
+}
+
+func newTestRepository(t testing.TB, conf *configs.Config) *RecordRepository {
+	t.Helper()
+	database, err := db.NewDb(conf, testLogger)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	if err := Migrate(database); err != nil {
+		t.Fatalf("failed to migrate database: %v", err)
+	}
+	return NewRecordRepository(database, testLogger)
+}
+
+type testServer struct {
+	router     *http.ServeMux
+	repository *RecordRepository
+}
+
+func newTestServer(t *testing.T, env map[string]string) *testServer {
+	t.Helper()
+	conf := newTestConfig(t, env)
+	repository := newTestRepository(t, conf)
+	events := NewBroker()
+	t.Cleanup(events.Close)
+	router := http.NewServeMux()
+	NewRecordHandler(router, &RecordHandlerDeps{
+		RecordRepository: repository,
+		Events:           events,
+		Config:           conf,
+		Logger:           testLogger,
+	})
+	return &testServer{router: router, repository: repository}
+}
+
+// do sends body as JSON, authenticated, unless it is nil.
+func (s *testServer) do(t *testing.T, method, target string, body any) *countingRecorder {
+	t.Helper()
+	return s.doWithHeader(t, nil, method, target, body)
+}
+
+// doWithHeader is do with extra request headers, which may replace the
+// default API key.
+func (s *testServer) doWithHeader(t *testing.T, header http.Header, method, target string, body any) *countingRecorder {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		raw, ok := body.(string)
+		if !ok {
+			encoded, err := json.Marshal(body)
+			if err != nil {
+				t.Fatalf("failed to encode body: %v", err)
+			}
+			raw = string(encoded)
+		}
+		reader = bytes.NewBufferString(raw)
+	}
+	r := httptest.NewRequest(method, target, reader)
+	if body != nil {
+		r.Header.Set("Content-Type", "application/json")
+	}
+	r.Header.Set(middleware.ApiKeyHeader, testApiKey)
+	for key, values := range header {
+		r.Header[key] = values
+	}
+	w := &countingRecorder{ResponseRecorder: httptest.NewRecorder()}
+	s.router.ServeHTTP(w, r)
+	return w
+}
+
+func (s *testServer) create(t *testing.T, name, phoneNumber string) RecordResponse {
+	t.Helper()
+	w := s.do(t, http.MethodPost, "/person", map[string]any{"name": name, "phone_number": phoneNumber})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, body = %s", w.Code, w.Body)
+	}
+	var resp RecordResponse
+	decodeBody(t, w, &resp)
+	return resp
+}
+
+// countingRecorder counts WriteHeader calls, which catches a handler that
+// writes an error and then falls through to the success path.
+type countingRecorder struct {
+	*httptest.ResponseRecorder
+	headers int
+}
+
+func (w *countingRecorder) WriteHeader(statusCode int) {
+	w.headers++
+	w.ResponseRecorder.WriteHeader(statusCode)
+}
+
+// decodeBody fails unless the body holds exactly one JSON value.
+func decodeBody(t *testing.T, w *countingRecorder, v any) {
+	t.Helper()
+	decoder := json.NewDecoder(bytes.NewReader(w.Body.Bytes()))
+	if err := decoder.Decode(v); err != nil {
+		t.Fatalf("failed to decode body %s: %v", w.Body, err)
+	}
+	if decoder.More() {
+		t.Fatalf("body holds more than one JSON value: %s", w.Body)
+	}
+}
@@ -0,0 +1,125 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/middleware"
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	HistoryActionUpdate     = "update"
+	HistoryActionDelete     = "delete"
+	HistoryActionHardDelete = "hard_delete"
+	HistoryActionRestore    = "restore"
+)
+
+// RecordHistory is one audit entry per change to a record. Old and new
+// values are JSON snapshots so entries outlive hard deletes and schema
+// changes to records.
+type RecordHistory struct {
+	ID        uint      `gorm:"primaryKey"`
+	RecordID  uuid.UUID `gorm:"index"`
+	Action    string    `gorm:"size:16"`
+	OldValues string
+	NewValues string
+	RequestID string
+	Subject   string
+	CreatedAt time.Time
+}
+
+func (RecordHistory) TableName() string {
+	return "record_history"
+}
+
+type recordSnapshot struct {
+	Name        string `json:"name" xml:"name"`
+	Age         int    `json:"age" xml:"age"`
+	Address     string `json:"address" xml:"address"`
+	PhoneNumber string `json:"phone_number" xml:"phone_number"`
+}
+
+type HistoryEntryResponse struct {
+	ID        uint            `json:"id" xml:"id"`
+	Action    string          `json:"action" xml:"action"`
+	Old       *recordSnapshot `json:"old" xml:"old,omitempty"`
+	New       *recordSnapshot `json:"new" xml:"new,omitempty"`
+	RequestID string          `json:"request_id,omitempty" xml:"request_id,omitempty"`
+	Subject   string          `json:"subject,omitempty" xml:"subject,omitempty"`
+	CreatedAt string          `json:"created_at" xml:"created_at"`
+}
+
+type HistoryResponse struct {
+	Items []HistoryEntryResponse `json:"items" xml:"items>entry"`
+}
+
+// newHistory snapshots before and after at call time, so callers may keep
+// mutating the records. Either may be nil, e.g. after is nil for a delete.
+func newHistory(ctx context.Context, action string, id uuid.UUID, before, after *Record) (*RecordHistory, error) {
+	oldValues, err := snapshot(before)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := snapshot(after)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordHistory{
+		RecordID:  id,
+		Action:    action,
+		OldValues: oldValues,
+		NewValues: newValues,
+		RequestID: middleware.RequestIDFromContext(ctx),
+		Subject:   middleware.SubjectFromContext(ctx),
+	}, nil
+}
+
+func writeHistory(ctx context.Context, tx *gorm.DB, action string, id uuid.UUID, before, after *Record) error {
+	entry, err := newHistory(ctx, action, id, before, after)
+	if err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+func snapshot(record *Record) (string, error) {
+	if record == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(recordSnapshot{
+		Name:        record.Name,
+		Age:         record.Age,
+		Address:     record.Address,
+		PhoneNumber: record.PhoneNumber,
+	})
+	return string(data), err
+}
+
+func toHistoryResponse(entry *RecordHistory) (HistoryEntryResponse, error) {
+	resp := HistoryEntryResponse{
+		ID:        entry.ID,
+		Action:    entry.Action,
+		RequestID: entry.RequestID,
+		Subject:   entry.Subject,
+		CreatedAt: entry.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	var err error
+	if resp.Old, err = parseSnapshot(entry.OldValues); err != nil {
+		return resp, err
+	}
+	resp.New, err = parseSnapshot(entry.NewValues)
+	return resp, err
+}
+
+func parseSnapshot(values string) (*recordSnapshot, error) {
+	if values == "" {
+		return nil, nil
+	}
+	var s recordSnapshot
+	if err := json.Unmarshal([]byte(values), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,42 @@
+package record
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+const maxIdempotencyKeyLength = 255
+
+var ErrIdempotencyKeyReused = errors.New("idempotency key was used with a different request")
+
+// IdempotencyKey remembers which record a create request with a given
+// Idempotency-Key header produced. Key is a digest of the client's scope
+// and header value, so one client's key never replays another client's
+// record, and RequestHash tells a retry from a different request that
+// reuses the key.
+type IdempotencyKey struct {
+	Key         string `gorm:"primaryKey;size:255"`
+	RequestHash string `gorm:"size:64"`
+	RecordID    uuid.UUID
+	CreatedAt   time.Time `gorm:"index"`
+}
+
+func NewIdempotencyKey(scope, key string, request any) (IdempotencyKey, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return IdempotencyKey{}, err
+	}
+	return IdempotencyKey{
+		Key:         digest(scope + "\x00" + key),
+		RequestHash: digest(string(body)),
+	}, nil
+}
+
+func digest(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
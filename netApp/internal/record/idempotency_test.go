@@ -0,0 +1,86 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/middleware"
+	"classroomWebGolang/pkg/response"
+	"net/http"
+	"testing"
+)
+
+func TestCreateRecordIdempotencyKey(t *testing.T) {
+	const otherApiKey = "other-key"
+	ann := map[string]any{"name": "Ann", "phone_number": "+15551234567"}
+	bob := map[string]any{"name": "Bob", "phone_number": "+15557654321"}
+	withKey := func(apiKey string) http.Header {
+		return http.Header{
+			"Idempotency-Key":       {"retry-1"},
+			middleware.ApiKeyHeader: {apiKey},
+		}
+	}
+
+	newServer := func(t *testing.T) (*testServer, RecordResponse) {
+		server := newTestServer(t, map[string]string{"API_KEYS": testApiKey + "," + otherApiKey})
+		w := server.doWithHeader(t, withKey(testApiKey), http.MethodPost, "/person", ann)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("first create: status = %d, body = %s", w.Code, w.Body)
+		}
+		var first RecordResponse
+		decodeBody(t, w, &first)
+		return server, first
+	}
+
+	t.Run("retry replays the record", func(t *testing.T) {
+		server, first := newServer(t)
+		w := server.doWithHeader(t, withKey(testApiKey), http.MethodPost, "/person", ann)
+		if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "true" {
+			t.Fatalf("status = %d, Idempotent-Replayed = %q, want a replayed 201", w.Code, w.Header().Get("Idempotent-Replayed"))
+		}
+		var replayed RecordResponse
+		decodeBody(t, w, &replayed)
+		if replayed.ID != first.ID {
+			t.Errorf("id = %s, want %s", replayed.ID, first.ID)
+		}
+	})
+
+	t.Run("different body is rejected", func(t *testing.T) {
+		server, _ := newServer(t)
+		w := server.doWithHeader(t, withKey(testApiKey), http.MethodPost, "/person", bob)
+		if w.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d, body = %s", w.Code, http.StatusConflict, w.Body)
+		}
+		var resp response.ErrorResponse
+		decodeBody(t, w, &resp)
+		if resp.Code != response.CodeIdempotencyKeyReused {
+			t.Errorf("code = %q, want %q", resp.Code, response.CodeIdempotencyKeyReused)
+		}
+	})
+
+	t.Run("keys are scoped per client", func(t *testing.T) {
+		server, first := newServer(t)
+		w := server.doWithHeader(t, withKey(otherApiKey), http.MethodPost, "/person", bob)
+		if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatalf("status = %d, Idempotent-Replayed = %q, want a fresh 201", w.Code, w.Header().Get("Idempotent-Replayed"))
+		}
+		var other RecordResponse
+		decodeBody(t, w, &other)
+		if other.ID == first.ID {
+			t.Error("another client's key replayed the first client's record")
+		}
+	})
+
+	t.Run("deleted record is created again", func(t *testing.T) {
+		server, first := newServer(t)
+		if w := server.do(t, http.MethodDelete, "/person/"+first.ID.String(), nil); w.Code != http.StatusNoContent {
+			t.Fatalf("delete: status = %d, body = %s", w.Code, w.Body)
+		}
+		w := server.doWithHeader(t, withKey(testApiKey), http.MethodPost, "/person", ann)
+		if w.Code != http.StatusCreated || w.Header().Get("Idempotent-Replayed") != "" {
+			t.Fatalf("status = %d, Idempotent-Replayed = %q, want a fresh 201, body = %s", w.Code, w.Header().Get("Idempotent-Replayed"), w.Body)
+		}
+		var recreated RecordResponse
+		decodeBody(t, w, &recreated)
+		if recreated.ID == first.ID {
+			t.Error("retry returned the deleted record")
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package record
+
+import "classroomWebGolang/pkg/db"
+
+// The embedded gorm.Model owns created_at, so the index can't be declared
+// with a struct tag. The composite index matches both the default sort,
+// read backwards, and keyset pagination, turning a full scan plus sort into
+// an index scan; BenchmarkGetRecordsAfter measures the difference. SQLite
+// only picks it once ANALYZE has collected statistics.
+const createdAtIndex = "CREATE INDEX IF NOT EXISTS idx_records_created_at_id ON records (created_at, id)"
+
+// Phone numbers only need to be unique among live rows, otherwise a
+// soft-deleted record blocks reusing its number. gorm tags can't express a
+// partial index, so it replaces the full unique index earlier versions
+// created from the struct tag.
+const (
+	dropPhoneNumberIndex = "DROP INDEX IF EXISTS idx_records_phone_number"
+	phoneNumberIndex     = "CREATE UNIQUE INDEX IF NOT EXISTS idx_records_phone_number_live ON records (phone_number) WHERE deleted_at IS NULL"
+)
+
+func CreateIndexes(database *db.Db) error {
+	for _, statement := range []string{createdAtIndex, dropPhoneNumberIndex, phoneNumberIndex} {
+		err := database.Exec(statement).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"testing"
+	"time"
+)
+
+func TestPhoneNumberUniqueAmongLiveRecords(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t, newTestConfig(t, nil))
+	newRecord := func() *Record {
+		return &Record{ID: uuid.New(), Name: "Ann", PhoneNumber: "+15551234567"}
+	}
+
+	first, err := repository.CreateRecord(ctx, newRecord())
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, err := repository.CreateRecord(ctx, newRecord()); !db.IsUniqueViolation(err) {
+		t.Fatalf("create duplicate of live record: err = %v, want unique violation", err)
+	}
+
+	if err := repository.DeleteRecord(ctx, first.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	second, err := repository.CreateRecord(ctx, newRecord())
+	if err != nil {
+		t.Fatalf("create after soft delete: %v", err)
+	}
+	if second.PhoneNumber != first.PhoneNumber {
+		t.Errorf("phone_number = %q, want %q", second.PhoneNumber, first.PhoneNumber)
+	}
+
+	if _, err := repository.RestoreRecord(ctx, first.ID); !db.IsUniqueViolation(err) {
+		t.Errorf("restore while the number is taken: err = %v, want unique violation", err)
+	}
+}
+
+// BenchmarkGetRecordsAfter reads a page from the middle of the table with
+// and without idx_records_created_at_id. Without it the database scans and
+// sorts every row; with it the cost no longer grows with the table. ANALYZE
+// gives the planner the statistics a long-running database already has;
+// on a fresh SQLite file it prefers idx_records_deleted_at and sorts.
+func BenchmarkGetRecordsAfter(b *testing.B) {
+	for _, withIndex := range []bool{true, false} {
+		for _, size := range []int{1_000, 10_000, 50_000} {
+			b.Run(fmt.Sprintf("index=%t/records=%d", withIndex, size), func(b *testing.B) {
+				ctx := context.Background()
+				repository := newTestRepository(b, newTestConfig(b, nil))
+				if !withIndex {
+					if err := repository.Database.Exec("DROP INDEX idx_records_created_at_id").Error; err != nil {
+						b.Fatalf("drop index: %v", err)
+					}
+				}
+				start := time.Now().Add(-time.Duration(size) * time.Second)
+				records := make([]*Record, size)
+				for i := range records {
+					records[i] = &Record{
+						ID:          uuid.New(),
+						Name:        "Ann",
+						PhoneNumber: fmt.Sprintf("+1555%07d", i),
+						Model:       gorm.Model{CreatedAt: start.Add(time.Duration(i) * time.Second)},
+					}
+				}
+				if _, err := repository.CreateRecords(ctx, records); err != nil {
+					b.Fatalf("create: %v", err)
+				}
+				if err := repository.Database.Exec("ANALYZE").Error; err != nil {
+					b.Fatalf("analyze: %v", err)
+				}
+				cursor := NewCursor(records[size/2])
+
+				b.ResetTimer()
+				for range b.N {
+					page, err := repository.GetRecordsAfter(ctx, RecordFilter{}, &cursor, 20)
+					if err != nil {
+						b.Fatalf("get: %v", err)
+					}
+					if len(page) != 20 {
+						b.Fatalf("got %d records, want 20", len(page))
+					}
+				}
+			})
+		}
+	}
+}
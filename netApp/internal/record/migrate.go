@@ -0,0 +1,13 @@
+package record
+
+import "classroomWebGolang/pkg/db"
+
+// Migrate brings the schema for records up to date. It is shared by the
+// migrate binary and AUTO_MIGRATE on server startup.
+func Migrate(database *db.Db) error {
+	err := db.Migrate(database, &Record{}, &IdempotencyKey{}, &RecordHistory{})
+	if err != nil {
+		return err
+	}
+	return CreateIndexes(database)
+}
@@ -1,6 +1,7 @@
 package record
 
 import (
+	"classroomWebGolang/pkg/phone"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/go-faker/faker/v4"
 	"github.com/google/uuid"
@@ -9,20 +10,55 @@ import (
 )
 
 type Record struct {
-	ID          uuid.UUID
-	Name        string
-	Age         int
-	Address     string
-	PhoneNumber string
-	*gorm.Model
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Age         int       `json:"age"`
+	Address     string    `json:"address"`
+	PhoneNumber string    `json:"phone_number"`
+	gorm.Model  `json:"-"`
 }
 
 func NewRecord() *Record {
 	return &Record{
 		ID:          uuid.New(),
 		Name:        faker.Name(),
-		Age:         rand.Int(),
+		Age:         rand.Intn(100),
 		Address:     gofakeit.Address().Address,
 		PhoneNumber: gofakeit.Phone(),
 	}
 }
+
+// NewRecordFromRequest builds a record from a create request. Omitted age
+// and address are generated as in NewRecord; explicit values, including 0
+// and "", are kept.
+func NewRecordFromRequest(payload *CreateRecordRequest) *Record {
+	record := &Record{ID: uuid.New(), Age: rand.Intn(100), Address: gofakeit.Address().Address}
+	if payload.Name != nil {
+		record.Name = *payload.Name
+	}
+	if payload.Age != nil {
+		record.Age = *payload.Age
+	}
+	if payload.Address != nil {
+		record.Address = *payload.Address
+	}
+	if payload.PhoneNumber != nil {
+		record.PhoneNumber = *payload.PhoneNumber
+	}
+	return record
+}
+
+func (Record) TableName() string {
+	return "records"
+}
+
+// BeforeSave stores phone numbers in canonical form so the unique index
+// compares like with like. Updates pass the new values as the statement
+// destination rather than the model, so that is normalized as well.
+func (r *Record) BeforeSave(tx *gorm.DB) error {
+	r.PhoneNumber = phone.Normalize(r.PhoneNumber)
+	if data, ok := tx.Statement.Dest.(*Record); ok && data != r {
+		data.PhoneNumber = phone.Normalize(data.PhoneNumber)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package record
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+func TestCreateRecordSetsTimestamps(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t, newTestConfig(t, nil))
+	before := time.Now().Add(-time.Second)
+
+	created, err := repository.CreateRecord(ctx, NewRecord())
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	stored, err := repository.GetRecordById(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+
+	for name, record := range map[string]*Record{"created": created, "stored": stored} {
+		if record.CreatedAt.Before(before) {
+			t.Errorf("%s: CreatedAt = %v, want after %v", name, record.CreatedAt, before)
+		}
+		if record.UpdatedAt.Before(before) {
+			t.Errorf("%s: UpdatedAt = %v, want after %v", name, record.UpdatedAt, before)
+		}
+		if record.DeletedAt.Valid {
+			t.Errorf("%s: DeletedAt = %v, want unset", name, record.DeletedAt.Time)
+		}
+	}
+}
+
+func TestBeforeSaveNormalizesPhoneNumber(t *testing.T) {
+	ctx := context.Background()
+	repository := newTestRepository(t, newTestConfig(t, nil))
+
+	created, err := repository.CreateRecord(ctx, &Record{ID: uuid.New(), Name: "Ann", PhoneNumber: "+1 (555) 123-4567"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if created.PhoneNumber != "+15551234567" {
+		t.Errorf("created phone_number = %q, want +15551234567", created.PhoneNumber)
+	}
+
+	data := &Record{Name: "Ann", PhoneNumber: "+1 555.765.4321"}
+	if _, err := repository.UpdateRecord(ctx, created.ID, data, time.Time{}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	stored, err := repository.GetRecordById(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if stored.PhoneNumber != "+15557654321" {
+		t.Errorf("updated phone_number = %q, want +15557654321", stored.PhoneNumber)
+	}
+}
+
+func TestNewRecordFromRequestFallback(t *testing.T) {
+	name, phone := "Ann", "+15551234567"
+	age, address := 0, ""
+
+	generated := NewRecordFromRequest(&CreateRecordRequest{Name: &name, PhoneNumber: &phone})
+	if generated.Address == "" {
+		t.Error("omitted address was not generated")
+	}
+	if generated.Age < 0 || generated.Age >= 100 {
+		t.Errorf("generated age = %d, want 0..99", generated.Age)
+	}
+
+	explicit := NewRecordFromRequest(&CreateRecordRequest{Name: &name, PhoneNumber: &phone, Age: &age, Address: &address})
+	if explicit.Age != 0 || explicit.Address != "" {
+		t.Errorf("explicit age, address = %d, %q, want 0, empty", explicit.Age, explicit.Address)
+	}
+}
@@ -0,0 +1,60 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+)
+
+type PaginationMode int
+
+const (
+	// PaginationEnvelope wraps items with total, limit, offset and cursor.
+	PaginationEnvelope PaginationMode = iota
+	// PaginationBare returns the items alone and moves the metadata into
+	// X-Total-Count, X-Limit, X-Offset and X-Next-Cursor headers.
+	PaginationBare
+)
+
+func parsePaginationMode(r *http.Request) (PaginationMode, error) {
+	raw := r.URL.Query().Get("envelope")
+	if raw == "" {
+		return PaginationEnvelope, nil
+	}
+	envelope, err := parseOptionalBool(r, "envelope")
+	if err != nil {
+		return PaginationEnvelope, err
+	}
+	if !envelope {
+		return PaginationBare, nil
+	}
+	return PaginationEnvelope, nil
+}
+
+func (h *RecordHandler) writePage(w http.ResponseWriter, r *http.Request, page GetRecordsResponse, mode PaginationMode) {
+	w.Header().Set("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	var body any = page
+	if mode == PaginationBare {
+		w.Header().Set("X-Limit", strconv.Itoa(page.Limit))
+		w.Header().Set("X-Offset", strconv.Itoa(page.Offset))
+		if page.NextCursor != "" {
+			w.Header().Set("X-Next-Cursor", page.NextCursor)
+		}
+		body = recordList(page.Items)
+	}
+	if err := response.Write(w, r, body, http.StatusOK); err != nil {
+		h.Logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// recordList is a bare JSON array. XML needs a single root element, so it
+// is rendered as <records> there.
+type recordList []RecordResponse
+
+func (l recordList) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Name.Local = "records"
+	return e.EncodeElement(struct {
+		Items []RecordResponse `xml:"record"`
+	}{Items: l}, start)
+}
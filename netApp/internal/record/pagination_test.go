@@ -0,0 +1,93 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"net/http"
+	"testing"
+)
+
+func TestGetRecordsPaginationMode(t *testing.T) {
+	server := newTestServer(t, nil)
+	for _, phoneNumber := range []string{"+15550000001", "+15550000002", "+15550000003"} {
+		server.create(t, "Ann", phoneNumber)
+	}
+
+	tests := []struct {
+		name       string
+		target     string
+		wantItems  int
+		wantHeader map[string]string
+	}{
+		{
+			name:      "envelope by default",
+			target:    "/person?limit=2",
+			wantItems: 2,
+		},
+		{
+			name:      "explicit envelope",
+			target:    "/person?limit=2&envelope=true",
+			wantItems: 2,
+		},
+		{
+			name:       "bare array",
+			target:     "/person?limit=2&offset=1&envelope=false",
+			wantItems:  2,
+			wantHeader: map[string]string{"X-Total-Count": "3", "X-Limit": "2", "X-Offset": "1"},
+		},
+		{
+			name:       "bare array with cursor",
+			target:     "/person?limit=2&after=&envelope=false",
+			wantItems:  2,
+			wantHeader: map[string]string{"X-Total-Count": "3", "X-Limit": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := server.do(t, http.MethodGet, tt.target, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+			}
+			if w.Header().Get("X-Total-Count") != "3" {
+				t.Errorf("X-Total-Count = %q, want 3", w.Header().Get("X-Total-Count"))
+			}
+			var items []RecordResponse
+			if tt.wantHeader == nil {
+				var resp GetRecordsResponse
+				decodeBody(t, w, &resp)
+				if resp.Total != 3 || resp.Limit != 2 {
+					t.Errorf("total = %d, limit = %d, want 3 and 2", resp.Total, resp.Limit)
+				}
+				items = resp.Items
+			} else {
+				decodeBody(t, w, &items)
+				for key, want := range tt.wantHeader {
+					if got := w.Header().Get(key); got != want {
+						t.Errorf("%s = %q, want %q", key, got, want)
+					}
+				}
+			}
+			if len(items) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(items), tt.wantItems)
+			}
+		})
+	}
+
+	t.Run("next cursor header", func(t *testing.T) {
+		w := server.do(t, http.MethodGet, "/person?limit=2&after=&envelope=false", nil)
+		if w.Header().Get("X-Next-Cursor") == "" {
+			t.Error("X-Next-Cursor is empty, want a cursor for the last page item")
+		}
+	})
+
+	t.Run("invalid envelope", func(t *testing.T) {
+		w := server.do(t, http.MethodGet, "/person?envelope=maybe", nil)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+		}
+		var resp response.ErrorResponse
+		decodeBody(t, w, &resp)
+		if resp.Code != response.CodeInvalidBoolean {
+			t.Errorf("code = %q, want %q", resp.Code, response.CodeInvalidBoolean)
+		}
+	})
+}
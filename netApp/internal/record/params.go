@@ -0,0 +1,135 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type InvalidIDError struct {
+	Value string
+	Err   error
+}
+
+func (e *InvalidIDError) Error() string {
+	return fmt.Sprintf("invalid id %q: %v", e.Value, e.Err)
+}
+
+func (e *InvalidIDError) Unwrap() error {
+	return e.Err
+}
+
+func ParseIDParam(r *http.Request) (uuid.UUID, error) {
+	value := r.PathValue("id")
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, &InvalidIDError{Value: value, Err: err}
+	}
+	return id, nil
+}
+
+func parsePagination(r *http.Request) (int, int) {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+	offset, err := strconv.Atoi(r.URL.Query().Get("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+func parseFilter(r *http.Request) (RecordFilter, error) {
+	filter := RecordFilter{Name: r.URL.Query().Get("name")}
+	minAge, err := parseOptionalInt(r, "min_age")
+	if err != nil {
+		return filter, err
+	}
+	maxAge, err := parseOptionalInt(r, "max_age")
+	if err != nil {
+		return filter, err
+	}
+	includeDeleted, err := parseOptionalBool(r, "include_deleted")
+	if err != nil {
+		return filter, err
+	}
+	onlyDeleted, err := parseOptionalBool(r, "only_deleted")
+	if err != nil {
+		return filter, err
+	}
+	from, err := parseOptionalTime(r, "from")
+	if err != nil {
+		return filter, err
+	}
+	to, err := parseOptionalTime(r, "to")
+	if err != nil {
+		return filter, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return filter, response.NewError(response.CodeInvalidRange, "from", "to")
+	}
+	filter.MinAge = minAge
+	filter.MaxAge = maxAge
+	filter.From = from
+	filter.To = to
+	filter.IncludeDeleted = includeDeleted
+	filter.OnlyDeleted = onlyDeleted
+	return filter, nil
+}
+
+func parseOptionalInt(r *http.Request, key string) (*int, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return nil, response.NewError(response.CodeInvalidInteger, key, raw)
+	}
+	return &value, nil
+}
+
+func parseOptionalTime(r *http.Request, key string) (*time.Time, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, response.NewError(response.CodeInvalidTimestamp, key, raw)
+	}
+	return &value, nil
+}
+
+func parseOptionalBool(r *http.Request, key string) (bool, error) {
+	raw := r.URL.Query().Get(key)
+	if raw == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, response.NewError(response.CodeInvalidBoolean, key, raw)
+	}
+	return value, nil
+}
+
+// parseUnmodifiedSince returns the zero time when the header is absent or
+// malformed, which callers treat as no precondition.
+func parseUnmodifiedSince(r *http.Request) time.Time {
+	raw := r.Header.Get("If-Unmodified-Since")
+	if raw == "" {
+		return time.Time{}
+	}
+	since, err := http.ParseTime(raw)
+	if err != nil {
+		return time.Time{}
+	}
+	return since
+}
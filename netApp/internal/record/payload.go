@@ -0,0 +1,194 @@
+package record
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/response"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// CreateRecordRequest uses pointers so a missing field can be told apart
+// from a zero value such as "age": 0.
+type CreateRecordRequest struct {
+	Name        *string `json:"name"`
+	Age         *int    `json:"age"`
+	Address     *string `json:"address"`
+	PhoneNumber *string `json:"phone_number"`
+}
+
+// Validate reports required fields missing from the body. Age and address
+// are optional and generated when omitted.
+func (c *CreateRecordRequest) Validate(cfg configs.ValidationConfig) error {
+	var errs ValidationErrors
+	if c.Name == nil {
+		errs = append(errs, FieldError{Field: "name", Message: "is required"})
+	}
+	if c.PhoneNumber == nil && cfg.PhoneRequired {
+		errs = append(errs, FieldError{Field: "phone_number", Message: "is required"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+type UpdateRecordRequest struct {
+	Name        string `json:"name"`
+	Age         int    `json:"age"`
+	Address     string `json:"address"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// PatchRecordRequest tells an omitted key from one sent as null: omitted
+// keys leave the field unchanged, while "address": "" and "address": null
+// both clear the address. The other fields are required and reject null.
+type PatchRecordRequest struct {
+	Name        *string `json:"name"`
+	Age         *int    `json:"age"`
+	Address     *string `json:"address"`
+	PhoneNumber *string `json:"phone_number"`
+	present     map[string]bool
+}
+
+func (p *PatchRecordRequest) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	p.present = make(map[string]bool, len(raw))
+	for key, value := range raw {
+		var target any
+		switch key {
+		case "name":
+			target = &p.Name
+		case "age":
+			target = &p.Age
+		case "address":
+			target = &p.Address
+		case "phone_number":
+			target = &p.PhoneNumber
+		default:
+			// Same message as Decoder.DisallowUnknownFields, which a custom
+			// unmarshaler bypasses.
+			return fmt.Errorf("json: unknown field %q", key)
+		}
+		if err := json.Unmarshal(value, target); err != nil {
+			// Name the key, as the decoder does for plain struct fields.
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &typeErr) {
+				typeErr.Field = key
+			}
+			return err
+		}
+		p.present[key] = true
+	}
+	return nil
+}
+
+// Apply copies the present keys onto record and returns the changed fields.
+func (p *PatchRecordRequest) Apply(record *Record) ([]string, error) {
+	var fields []string
+	var errs ValidationErrors
+	if p.present["name"] && p.Name == nil {
+		errs = append(errs, FieldError{Field: "name", Message: "must not be null"})
+	}
+	if p.present["age"] && p.Age == nil {
+		errs = append(errs, FieldError{Field: "age", Message: "must not be null"})
+	}
+	if p.present["phone_number"] && p.PhoneNumber == nil {
+		errs = append(errs, FieldError{Field: "phone_number", Message: "must not be null"})
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	if p.Name != nil {
+		record.Name = *p.Name
+		fields = append(fields, "Name")
+	}
+	if p.Age != nil {
+		record.Age = *p.Age
+		fields = append(fields, "Age")
+	}
+	if p.present["address"] {
+		record.Address = ""
+		if p.Address != nil {
+			record.Address = *p.Address
+		}
+		fields = append(fields, "Address")
+	}
+	if p.PhoneNumber != nil {
+		record.PhoneNumber = *p.PhoneNumber
+		fields = append(fields, "PhoneNumber")
+	}
+	return fields, nil
+}
+
+type SearchRecordsRequest struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	MinAge   *int   `json:"min_age"`
+	MaxAge   *int   `json:"max_age"`
+	Operator string `json:"operator"`
+	Sort     string `json:"sort"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+}
+
+type BulkUpdateFilter struct {
+	Name   string `json:"name"`
+	MinAge *int   `json:"min_age"`
+	MaxAge *int   `json:"max_age"`
+}
+
+type BulkUpdateRequest struct {
+	Filter  BulkUpdateFilter   `json:"filter"`
+	Updates PatchRecordRequest `json:"updates"`
+}
+
+type BulkUpdateResponse struct {
+	Updated int64 `json:"updated" xml:"updated"`
+}
+
+type BatchGetRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type BatchGetResponse struct {
+	Items []RecordResponse `json:"items" xml:"items>record"`
+}
+
+type BulkCreateResponse struct {
+	Created int `json:"created" xml:"created"`
+}
+
+type ImportRowError struct {
+	Row   int    `json:"row" xml:"row"`
+	Error string `json:"error" xml:"error"`
+}
+
+type ImportResponse struct {
+	Inserted int              `json:"inserted" xml:"inserted"`
+	Rejected int              `json:"rejected" xml:"rejected"`
+	Errors   []ImportRowError `json:"errors" xml:"errors>error"`
+}
+
+type GetRecordsResponse struct {
+	Items      []RecordResponse `json:"items" xml:"items>record"`
+	Total      int64            `json:"total" xml:"total"`
+	Limit      int              `json:"limit" xml:"limit"`
+	Offset     int              `json:"offset" xml:"offset"`
+	NextCursor string           `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"`
+}
+
+type CountResponse struct {
+	Count int64 `json:"count" xml:"count"`
+}
+
+type ValidationErrorResponse struct {
+	Error  string           `json:"error"`
+	Code   response.Code    `json:"code"`
+	Status int              `json:"status"`
+	Errors ValidationErrors `json:"errors"`
+}
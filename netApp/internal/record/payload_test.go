@@ -0,0 +1,61 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"net/http"
+	"testing"
+)
+
+func TestPatchRecordAddress(t *testing.T) {
+	const address = "Main St 1"
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantName    string
+		wantAddress string
+		wantCode    response.Code
+	}{
+		{"omitted key leaves address", `{"name":"Bob"}`, http.StatusOK, "Bob", address, ""},
+		{"empty string clears address", `{"address":""}`, http.StatusOK, "Ann", "", ""},
+		{"null clears address", `{"address":null}`, http.StatusOK, "Ann", "", ""},
+		{"new address", `{"address":"Side St 2"}`, http.StatusOK, "Ann", "Side St 2", ""},
+		{"null name", `{"name":null}`, http.StatusUnprocessableEntity, "Ann", address, response.CodeValidationFailed},
+		{"wrong type", `{"address":1}`, http.StatusBadRequest, "Ann", address, response.CodeInvalidType},
+		{"no keys", `{}`, http.StatusBadRequest, "Ann", address, response.CodeEmptyPatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, nil)
+			w := server.do(t, http.MethodPost, "/person", map[string]any{"name": "Ann", "address": address, "phone_number": "+15551234567"})
+			if w.Code != http.StatusCreated {
+				t.Fatalf("create: status = %d, body = %s", w.Code, w.Body)
+			}
+			var created RecordResponse
+			decodeBody(t, w, &created)
+			target := "/person/" + created.ID.String()
+
+			w = server.do(t, http.MethodPatch, target, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body = %s", w.Code, tt.wantStatus, w.Body)
+			}
+			if tt.wantCode != "" {
+				var resp response.ErrorResponse
+				decodeBody(t, w, &resp)
+				if resp.Code != tt.wantCode {
+					t.Errorf("code = %q, want %q", resp.Code, tt.wantCode)
+				}
+			}
+
+			w = server.do(t, http.MethodGet, target, nil)
+			var stored RecordResponse
+			decodeBody(t, w, &stored)
+			if stored.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", stored.Name, tt.wantName)
+			}
+			if stored.Address != tt.wantAddress {
+				t.Errorf("address = %q, want %q", stored.Address, tt.wantAddress)
+			}
+		})
+	}
+}
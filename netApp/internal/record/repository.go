@@ -1,28 +1,516 @@
 package record
 
-import "classroomWebGolang/pkg/db"
+import (
+	"classroomWebGolang/pkg/db"
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+var (
+	ErrRecordNotDeleted = errors.New("record is not deleted")
+	ErrRecordModified   = errors.New("record has been modified")
+)
 
 type RecordRepository struct {
 	Database *db.Db
+	Logger   *slog.Logger
 }
 
-func NewRecordRepository(db *db.Db) *RecordRepository {
-	return &RecordRepository{Database: db}
+func NewRecordRepository(db *db.Db, logger *slog.Logger) *RecordRepository {
+	return &RecordRepository{Database: db, Logger: logger}
 }
 
-func (r *RecordRepository) CreateRecord(Record *Record) (*Record, error) {
-	result := r.Database.Create(Record)
+func (r *RecordRepository) CreateRecord(ctx context.Context, Record *Record) (*Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	result := tx.Create(Record)
 	if result.Error != nil {
+		r.logError("failed to create record", result.Error)
 		return nil, result.Error
 	}
 	return Record, nil
 }
 
-func (r *RecordRepository) GetRecords() ([]Record, error) {
+// CreateRecordIdempotent creates record unless key was used within ttl, in
+// which case the record created for it is returned and replayed is true.
+// Expired keys are purged on every insert.
+func (r *RecordRepository) CreateRecordIdempotent(ctx context.Context, record *Record, key IdempotencyKey, ttl time.Duration) (*Record, bool, error) {
+	existing, err := r.findIdempotentRecord(ctx, key, ttl)
+	if err != nil || existing != nil {
+		return existing, existing != nil, err
+	}
+	err = r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		err := tx.Where("created_at < ?", time.Now().Add(-ttl)).Delete(&IdempotencyKey{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Create(record).Error
+		if err != nil {
+			return err
+		}
+		key.RecordID = record.ID
+		return tx.Create(&key).Error
+	})
+	if db.IsUniqueViolation(err) {
+		// A concurrent request with the same key may have won the race.
+		existing, findErr := r.findIdempotentRecord(ctx, key, ttl)
+		if errors.Is(findErr, ErrIdempotencyKeyReused) {
+			return nil, false, findErr
+		}
+		if findErr == nil && existing != nil {
+			return existing, true, nil
+		}
+	}
+	if err != nil {
+		r.logError("failed to create record", err)
+		return nil, false, err
+	}
+	return record, false, nil
+}
+
+// findIdempotentRecord treats a key whose record has since been deleted as
+// unused, dropping it so the retry creates a fresh record.
+func (r *RecordRepository) findIdempotentRecord(ctx context.Context, key IdempotencyKey, ttl time.Duration) (*Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var stored IdempotencyKey
+	err := tx.Where("key = ? AND created_at >= ?", key.Key, time.Now().Add(-ttl)).Limit(1).Find(&stored).Error
+	if err != nil {
+		r.logError("failed to get idempotency key", err)
+		return nil, err
+	}
+	if stored.Key == "" {
+		return nil, nil
+	}
+	if stored.RequestHash != key.RequestHash {
+		return nil, ErrIdempotencyKeyReused
+	}
+	record, err := r.GetRecordById(ctx, stored.RecordID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		err = tx.Delete(&stored).Error
+		if err != nil {
+			r.logError("failed to delete idempotency key", err)
+			return nil, err
+		}
+		return nil, nil
+	}
+	return record, err
+}
+
+func (r *RecordRepository) CreateRecords(ctx context.Context, records []*Record) (int, error) {
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		return tx.CreateInBatches(records, 100).Error
+	})
+	if err != nil {
+		r.logError("failed to create records", err)
+		return 0, err
+	}
+	return len(records), nil
+}
+
+func (r *RecordRepository) GetRecords(ctx context.Context, filter Filter, order string, limit, offset int, columns ...string) ([]Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	if len(columns) > 0 {
+		tx = tx.Select(columns)
+	}
+	var records []Record
+	result := filter.Apply(tx).Order(order).Limit(limit).Offset(offset).Find(&records)
+	if result.Error != nil {
+		r.logError("failed to get records", result.Error)
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+func (r *RecordRepository) GetRecordsAfter(ctx context.Context, filter Filter, cursor *Cursor, limit int, columns ...string) ([]Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	if len(columns) > 0 {
+		tx = tx.Select(columns)
+	}
+	query := filter.Apply(tx)
+	if cursor != nil {
+		query = query.Where("(created_at, id) > (?, ?)", cursor.CreatedAt, cursor.ID)
+	}
 	var records []Record
-	result := r.Database.Find(&records)
+	result := query.Order("created_at, id").Limit(limit).Find(&records)
 	if result.Error != nil {
+		r.logError("failed to get records", result.Error)
 		return nil, result.Error
 	}
 	return records, nil
 }
+
+// EachRecord streams all records ordered by (created_at, id) through fn
+// one row at a time, so memory stays flat however large the table is. The
+// query runs for as long as the caller keeps consuming, so it is bound by
+// ctx rather than the query timeout. Iteration stops at the first error
+// from fn or ctx.
+func (r *RecordRepository) EachRecord(ctx context.Context, fn func(record *Record) error) error {
+	tx := r.Database.WithContext(ctx)
+	rows, err := tx.Model(&Record{}).Order("created_at, id").Rows()
+	if err != nil {
+		r.logError("failed to stream records", err)
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		err = ctx.Err()
+		if err != nil {
+			return err
+		}
+		var record Record
+		err = tx.ScanRows(rows, &record)
+		if err != nil {
+			r.logError("failed to scan record", err)
+			return err
+		}
+		err = fn(&record)
+		if err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *RecordRepository) CountRecords(ctx context.Context, filter Filter) (int64, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var count int64
+	result := filter.Apply(tx.Model(&Record{})).Count(&count)
+	if result.Error != nil {
+		r.logError("failed to count records", result.Error)
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *RecordRepository) GetRecordStats(ctx context.Context, filter Filter) (*RecordStats, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var stats RecordStats
+	result := filter.Apply(tx.Model(&Record{})).
+		Select("COUNT(*) AS count, AVG(age) AS avg_age, MIN(age) AS min_age, MAX(age) AS max_age").
+		Scan(&stats)
+	if result.Error != nil {
+		r.logError("failed to get record stats", result.Error)
+		return nil, result.Error
+	}
+	return &stats, nil
+}
+
+// FindDuplicates returns one page of live records grouped by the duplicate
+// key by, largest clusters first, together with the total number of
+// clusters.
+func (r *RecordRepository) FindDuplicates(ctx context.Context, by string, limit, offset int) ([]DuplicateCluster, int64, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	expr := duplicateKeyExpression(tx, by)
+	groups := tx.Model(&Record{}).
+		Select(expr + " AS duplicate_key, COUNT(*) AS count").
+		Group(expr).
+		Having("COUNT(*) > 1")
+	var total int64
+	err := tx.Table("(?) AS clusters", groups).Count(&total).Error
+	if err != nil {
+		r.logError("failed to count duplicates", err)
+		return nil, 0, err
+	}
+	var rows []struct {
+		DuplicateKey string
+		Count        int64
+	}
+	err = groups.Order("count DESC, duplicate_key").Limit(limit).Offset(offset).Scan(&rows).Error
+	if err != nil {
+		r.logError("failed to find duplicates", err)
+		return nil, 0, err
+	}
+	if len(rows) == 0 {
+		return []DuplicateCluster{}, total, nil
+	}
+	keys := make([]string, len(rows))
+	for i, row := range rows {
+		keys[i] = row.DuplicateKey
+	}
+	var members []struct {
+		Record       `gorm:"embedded"`
+		DuplicateKey string
+	}
+	err = tx.Model(&Record{}).
+		Select("records.*, "+expr+" AS duplicate_key").
+		Where(expr+" IN ?", keys).
+		Order("created_at, id").
+		Scan(&members).Error
+	if err != nil {
+		r.logError("failed to get duplicate records", err)
+		return nil, 0, err
+	}
+	clusters := make([]DuplicateCluster, len(rows))
+	index := make(map[string]int, len(rows))
+	for i, row := range rows {
+		clusters[i] = DuplicateCluster{Key: row.DuplicateKey, Count: row.Count}
+		index[row.DuplicateKey] = i
+	}
+	for _, member := range members {
+		i := index[member.DuplicateKey]
+		clusters[i].Records = append(clusters[i].Records, member.Record)
+	}
+	return clusters, total, nil
+}
+
+func (r *RecordRepository) GetRecordById(ctx context.Context, id uuid.UUID) (*Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var record Record
+	result := tx.First(&record, "id = ?", id)
+	if result.Error != nil {
+		r.logError("failed to get record", result.Error)
+		return nil, result.Error
+	}
+	return &record, nil
+}
+
+// ExistingPhoneNumbers reports which of phones belong to live records.
+// Lookups are chunked to stay below driver parameter limits.
+func (r *RecordRepository) ExistingPhoneNumbers(ctx context.Context, phones []string) (map[string]bool, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	existing := make(map[string]bool)
+	for chunk := range slices.Chunk(phones, 500) {
+		var found []string
+		err := tx.Model(&Record{}).Where("phone_number IN ?", chunk).Pluck("phone_number", &found).Error
+		if err != nil {
+			r.logError("failed to look up phone numbers", err)
+			return nil, err
+		}
+		for _, number := range found {
+			existing[number] = true
+		}
+	}
+	return existing, nil
+}
+
+// Exists checks for the record without loading it. Soft-deleted records
+// only count when includeDeleted is set.
+func (r *RecordRepository) Exists(ctx context.Context, id uuid.UUID, includeDeleted bool) (bool, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	if includeDeleted {
+		tx = tx.Unscoped()
+	}
+	var found []int
+	result := tx.Model(&Record{}).Select("1").Where("id = ?", id).Limit(1).Find(&found)
+	if result.Error != nil {
+		r.logError("failed to check record existence", result.Error)
+		return false, result.Error
+	}
+	return len(found) > 0, nil
+}
+
+func (r *RecordRepository) GetRecordsByIds(ctx context.Context, ids []uuid.UUID) ([]Record, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var records []Record
+	result := tx.Where("id IN ?", ids).Order("created_at, id").Find(&records)
+	if result.Error != nil {
+		r.logError("failed to get records by ids", result.Error)
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+func (r *RecordRepository) DeleteRecord(ctx context.Context, id uuid.UUID) error {
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		var record Record
+		err := tx.Clauses(lockingClause(tx)...).First(&record, "id = ?", id).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Delete(&record).Error
+		if err != nil {
+			return err
+		}
+		return writeHistory(ctx, tx, HistoryActionDelete, id, &record, nil)
+	})
+	if err != nil {
+		r.logError("failed to delete record", err)
+		return err
+	}
+	return nil
+}
+
+func (r *RecordRepository) HardDeleteRecord(ctx context.Context, id uuid.UUID) error {
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		var record Record
+		err := tx.Unscoped().Clauses(lockingClause(tx)...).First(&record, "id = ?", id).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Unscoped().Delete(&record).Error
+		if err != nil {
+			return err
+		}
+		return writeHistory(ctx, tx, HistoryActionHardDelete, id, &record, nil)
+	})
+	if err != nil {
+		r.logError("failed to hard delete record", err)
+		return err
+	}
+	return nil
+}
+
+func (r *RecordRepository) RestoreRecord(ctx context.Context, id uuid.UUID) (*Record, error) {
+	var record Record
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		err := tx.Unscoped().First(&record, "id = ?", id).Error
+		if err != nil {
+			return err
+		}
+		if !record.DeletedAt.Valid {
+			return ErrRecordNotDeleted
+		}
+		record.DeletedAt = gorm.DeletedAt{}
+		err = tx.Unscoped().Model(&record).Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+		return writeHistory(ctx, tx, HistoryActionRestore, id, nil, &record)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrRecordNotDeleted) && !db.IsUniqueViolation(err) {
+			r.logError("failed to restore record", err)
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// UpdateRecordsByFilter applies fields from data to every record matching
+// filter in a single UPDATE. When more than max records match nothing is
+// changed and the match count is returned with ErrTooManyMatches.
+func (r *RecordRepository) UpdateRecordsByFilter(ctx context.Context, filter Filter, fields []string, data *Record, max int64) (int64, error) {
+	var affected int64
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		var count int64
+		err := filter.Apply(tx.Model(&Record{})).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > max {
+			affected = count
+			return ErrTooManyMatches
+		}
+		var before []Record
+		err = filter.Apply(tx.Model(&Record{})).Clauses(lockingClause(tx)...).Find(&before).Error
+		if err != nil {
+			return err
+		}
+		if len(before) == 0 {
+			return nil
+		}
+		ids := make([]uuid.UUID, len(before))
+		for i := range before {
+			ids[i] = before[i].ID
+		}
+		result := tx.Model(&Record{}).Where("id IN ?", ids).Select(append(fields, "UpdatedAt")).Updates(data)
+		if result.Error != nil {
+			return result.Error
+		}
+		affected = result.RowsAffected
+		var after []Record
+		err = tx.Where("id IN ?", ids).Find(&after).Error
+		if err != nil {
+			return err
+		}
+		updated := make(map[uuid.UUID]*Record, len(after))
+		for i := range after {
+			updated[after[i].ID] = &after[i]
+		}
+		entries := make([]*RecordHistory, 0, len(before))
+		for i := range before {
+			entry, err := newHistory(ctx, HistoryActionUpdate, before[i].ID, &before[i], updated[before[i].ID])
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return tx.CreateInBatches(entries, 100).Error
+	})
+	if err != nil {
+		if !errors.Is(err, ErrTooManyMatches) {
+			r.logError("failed to update records", err)
+		}
+		return affected, err
+	}
+	return affected, nil
+}
+
+func (r *RecordRepository) UpdateRecord(ctx context.Context, id uuid.UUID, data *Record, unmodifiedSince time.Time) (*Record, error) {
+	return r.PatchRecord(ctx, id, []string{"Name", "Age", "Address", "PhoneNumber"}, data, unmodifiedSince)
+}
+
+// PatchRecord fails with ErrRecordModified when unmodifiedSince is set and
+// the row was updated after it. HTTP dates have second precision, so the
+// stored timestamp is truncated before comparing.
+func (r *RecordRepository) PatchRecord(ctx context.Context, id uuid.UUID, fields []string, data *Record, unmodifiedSince time.Time) (*Record, error) {
+	var record Record
+	err := r.Database.Transaction(ctx, func(tx *gorm.DB) error {
+		err := tx.Clauses(lockingClause(tx)...).First(&record, "id = ?", id).Error
+		if err != nil {
+			return err
+		}
+		if !unmodifiedSince.IsZero() && record.UpdatedAt.Truncate(time.Second).After(unmodifiedSince) {
+			return ErrRecordModified
+		}
+		data.ID = id
+		before := record
+		err = tx.Model(&record).
+			Select(fields).
+			Updates(data).Error
+		if err != nil {
+			return err
+		}
+		return writeHistory(ctx, tx, HistoryActionUpdate, id, &before, &record)
+	})
+	if err != nil {
+		if !errors.Is(err, ErrRecordModified) {
+			r.logError("failed to update record", err)
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+func lockingClause(tx *gorm.DB) []clause.Expression {
+	if tx.Dialector.Name() == "sqlite" {
+		return nil
+	}
+	return []clause.Expression{clause.Locking{Strength: "UPDATE"}}
+}
+
+// GetRecordHistory lists the audit entries of a record, oldest first. It
+// also works for records that were hard deleted.
+func (r *RecordRepository) GetRecordHistory(ctx context.Context, id uuid.UUID) ([]RecordHistory, error) {
+	tx, cancel := r.Database.WithTimeout(ctx)
+	defer cancel()
+	var entries []RecordHistory
+	result := tx.Where("record_id = ?", id).Order("created_at, id").Find(&entries)
+	if result.Error != nil {
+		r.logError("failed to get record history", result.Error)
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (r *RecordRepository) logError(msg string, err error) {
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		r.Logger.Error(msg, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"gorm.io/gorm"
+	"strings"
+)
+
+const (
+	SearchOperatorAnd = "AND"
+	SearchOperatorOr  = "OR"
+)
+
+func (s *SearchRecordsRequest) Validate() error {
+	s.Operator = strings.ToUpper(s.Operator)
+	if s.Operator == "" {
+		s.Operator = SearchOperatorAnd
+	}
+	if s.Operator != SearchOperatorAnd && s.Operator != SearchOperatorOr {
+		return response.NewError(response.CodeInvalidChoice, "operator", s.Operator, "AND, OR")
+	}
+	if s.MinAge != nil && s.MaxAge != nil && *s.MinAge > *s.MaxAge {
+		return response.NewError(response.CodeInvalidRange, "min_age", "max_age")
+	}
+	if s.Limit <= 0 {
+		s.Limit = defaultLimit
+	}
+	if s.Limit > maxLimit {
+		s.Limit = maxLimit
+	}
+	if s.Offset < 0 {
+		s.Offset = 0
+	}
+	return nil
+}
+
+// Apply joins the conditions with the requested operator inside a single
+// group, so OR never escapes past the soft-delete clause gorm adds.
+// Column names come from fixed strings; user input only reaches the
+// placeholders.
+func (s SearchRecordsRequest) Apply(db *gorm.DB) *gorm.DB {
+	var clauses []string
+	var args []any
+	if s.Name != "" {
+		clauses = append(clauses, containsClause(db, "name"))
+		args = append(args, s.Name)
+	}
+	if s.Address != "" {
+		clauses = append(clauses, containsClause(db, "address"))
+		args = append(args, s.Address)
+	}
+	switch {
+	case s.MinAge != nil && s.MaxAge != nil:
+		clauses = append(clauses, "age BETWEEN ? AND ?")
+		args = append(args, *s.MinAge, *s.MaxAge)
+	case s.MinAge != nil:
+		clauses = append(clauses, "age >= ?")
+		args = append(args, *s.MinAge)
+	case s.MaxAge != nil:
+		clauses = append(clauses, "age <= ?")
+		args = append(args, *s.MaxAge)
+	}
+	if len(clauses) == 0 {
+		return db
+	}
+	return db.Where("("+strings.Join(clauses, " "+s.Operator+" ")+")", args...)
+}
@@ -0,0 +1,37 @@
+package record
+
+import (
+	"classroomWebGolang/pkg/response"
+	"errors"
+	"testing"
+)
+
+func TestSearchRecordsRequestValidate(t *testing.T) {
+	age := func(v int) *int { return &v }
+	tests := []struct {
+		name     string
+		req      SearchRecordsRequest
+		wantCode response.Code
+	}{
+		{"no conditions", SearchRecordsRequest{}, ""},
+		{"open range", SearchRecordsRequest{MinAge: age(10)}, ""},
+		{"single age", SearchRecordsRequest{MinAge: age(30), MaxAge: age(30)}, ""},
+		{"inverted range", SearchRecordsRequest{MinAge: age(50), MaxAge: age(10)}, response.CodeInvalidRange},
+		{"unknown operator", SearchRecordsRequest{Operator: "xor"}, response.CodeInvalidChoice},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var codedErr *response.CodedError
+			if !errors.As(err, &codedErr) || codedErr.Code != tt.wantCode {
+				t.Errorf("Validate() = %v, want code %q", err, tt.wantCode)
+			}
+		})
+	}
+}
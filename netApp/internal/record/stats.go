@@ -0,0 +1,10 @@
+package record
+
+// RecordStats summarizes the age of matching records. The aggregates are
+// nil when nothing matches.
+type RecordStats struct {
+	Count  int64    `json:"count" xml:"count"`
+	AvgAge *float64 `json:"avg_age" xml:"avg_age,omitempty"`
+	MinAge *int     `json:"min_age" xml:"min_age,omitempty"`
+	MaxAge *int     `json:"max_age" xml:"max_age,omitempty"`
+}
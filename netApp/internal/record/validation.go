@@ -0,0 +1,73 @@
+package record
+
+import (
+	"classroomWebGolang/configs"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors collects every failed field instead of stopping at the
+// first one.
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fieldErr := range e {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return "invalid fields: " + strings.Join(parts, ", ")
+}
+
+// WithPrefix nests the field paths under prefix, e.g. "age" becomes
+// "[3].age" for the fourth record of a batch.
+func (e ValidationErrors) WithPrefix(prefix string) ValidationErrors {
+	nested := make(ValidationErrors, len(e))
+	for i, fieldErr := range e {
+		nested[i] = FieldError{Field: prefix + fieldErr.Field, Message: fieldErr.Message}
+	}
+	return nested
+}
+
+func (r *Record) Validate(cfg configs.ValidationConfig) error {
+	var errs ValidationErrors
+	if err := validateName(r.Name, cfg); err != nil {
+		errs = append(errs, FieldError{Field: "name", Message: err.Error()})
+	}
+	if err := validateAge(r.Age, cfg); err != nil {
+		errs = append(errs, FieldError{Field: "age", Message: err.Error()})
+	}
+	if cfg.PhoneRequired && r.PhoneNumber == "" {
+		errs = append(errs, FieldError{Field: "phone_number", Message: "must not be empty"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validateName counts characters rather than bytes so Cyrillic names get the
+// same limits as Latin ones.
+func validateName(name string, cfg configs.ValidationConfig) error {
+	length := utf8.RuneCountInString(name)
+	if length == 0 && cfg.MinNameLength > 0 {
+		return errors.New("must not be empty")
+	}
+	if length < cfg.MinNameLength || length > cfg.MaxNameLength {
+		return fmt.Errorf("must be between %d and %d characters", cfg.MinNameLength, cfg.MaxNameLength)
+	}
+	return nil
+}
+
+func validateAge(age int, cfg configs.ValidationConfig) error {
+	if age < 0 || age > cfg.MaxAge {
+		return fmt.Errorf("must be between 0 and %d", cfg.MaxAge)
+	}
+	return nil
+}
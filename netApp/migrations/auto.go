@@ -1,25 +1,33 @@
 package main
 
 import (
+	"classroomWebGolang/configs"
 	"classroomWebGolang/internal/record"
-	"github.com/joho/godotenv"
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
-	"log"
+	"classroomWebGolang/pkg/db"
+	"log/slog"
 	"os"
 )
 
 func main() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	conf := configs.LoadConfig()
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: conf.Log.Level}))
+
+	if err := conf.Validate(); err != nil {
+		logger.Error("invalid configuration", "error", err)
+		os.Exit(1)
 	}
-	db, err := gorm.Open(postgres.Open(os.Getenv("DB_DSN")), &gorm.Config{})
+
+	database, err := db.NewDbWithRetry(conf, logger)
 	if err != nil {
-		log.Fatalf("Error connecting to database: %v", err)
+		logger.Error("Error connecting to database", "error", err)
+		os.Exit(1)
 	}
-	err = db.AutoMigrate(&record.Record{})
+	defer database.Close()
+
+	err = record.Migrate(database)
 	if err != nil {
-		log.Fatalf("Error creating record: %v", err)
+		logger.Error("Error migrating database", "error", err)
+		os.Exit(1)
 	}
 }
@@ -2,18 +2,129 @@ package db
 
 import (
 	"classroomWebGolang/configs"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"log/slog"
+	"time"
 )
 
 type Db struct {
 	*gorm.DB
+	QueryTimeout time.Duration
+	Schema       string
 }
 
-func NewDb(conf *configs.Config) (*Db, error) {
-	db, err := gorm.Open(postgres.Open(conf.Db.Dsn), &gorm.Config{})
+func NewDb(conf *configs.Config, logger *slog.Logger) (*Db, error) {
+	dialector, err := newDialector(conf.Db)
 	if err != nil {
 		return nil, err
 	}
-	return &Db{db}, nil
+	gormConfig := &gorm.Config{Logger: newQueryLogger(logger, conf.Db.LogLevel)}
+	if conf.Db.TimeZone != "" {
+		location, err := time.LoadLocation(conf.Db.TimeZone)
+		if err != nil {
+			return nil, err
+		}
+		gormConfig.NowFunc = func() time.Time {
+			return time.Now().In(location)
+		}
+	}
+	db, err := gorm.Open(dialector, gormConfig)
+	if err != nil {
+		return nil, err
+	}
+	sqlDb, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDb.SetMaxOpenConns(conf.Db.MaxOpenConns)
+	sqlDb.SetMaxIdleConns(conf.Db.MaxIdleConns)
+	sqlDb.SetConnMaxLifetime(conf.Db.ConnMaxLifetime)
+	return &Db{DB: db, QueryTimeout: conf.Db.QueryTimeout, Schema: conf.Db.Schema}, nil
+}
+
+func newDialector(conf configs.DbConfig) (gorm.Dialector, error) {
+	switch conf.Driver {
+	case "postgres":
+		dsn := withRuntimeParam(conf.Dsn, "search_path", conf.Schema)
+		return postgres.Open(withRuntimeParam(dsn, "TimeZone", conf.TimeZone)), nil
+	case "sqlite":
+		return sqlite.Open(conf.Dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %q", conf.Driver)
+	}
+}
+
+func NewDbWithRetry(conf *configs.Config, logger *slog.Logger) (*Db, error) {
+	attempts := max(conf.Db.ConnectAttempts, 1)
+	delay := conf.Db.ConnectDelay
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var db *Db
+		db, err = NewDb(conf, logger)
+		if err == nil {
+			return db, nil
+		}
+		logger.Warn("failed to connect to database", "attempt", attempt, "attempts", attempts, "error", err)
+		if attempt < attempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	return nil, err
+}
+
+func (db *Db) WithTimeout(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if db.QueryTimeout <= 0 {
+		ctx, cancel := context.WithCancel(ctx)
+		return db.DB.WithContext(ctx), cancel
+	}
+	ctx, cancel := context.WithTimeout(ctx, db.QueryTimeout)
+	return db.DB.WithContext(ctx), cancel
+}
+
+func (db *Db) Close() error {
+	sqlDb, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDb.Close()
+}
+
+func (db *Db) Ping(ctx context.Context) error {
+	sqlDb, err := db.DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDb.PingContext(ctx)
+}
+
+func (db *Db) Stats() (sql.DBStats, error) {
+	sqlDb, err := db.DB.DB()
+	if err != nil {
+		return sql.DBStats{}, err
+	}
+	return sqlDb.Stats(), nil
+}
+
+func (db *Db) Transaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	tx, cancel := db.WithTimeout(ctx)
+	defer cancel()
+	return tx.Transaction(fn)
+}
+
+// Migrate creates the configured schema before the tables, since
+// AutoMigrate creates them in the first schema on the search path.
+func Migrate(db *Db, models ...interface{}) error {
+	if db.Schema != "" && db.Dialector.Name() == "postgres" {
+		err := db.Exec(`CREATE SCHEMA IF NOT EXISTS "` + db.Schema + `"`).Error
+		if err != nil {
+			return err
+		}
+	}
+	return db.AutoMigrate(models...)
 }
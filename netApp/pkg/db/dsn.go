@@ -0,0 +1,26 @@
+package db
+
+import (
+	"net/url"
+	"strings"
+)
+
+// withRuntimeParam adds a connection runtime parameter such as search_path
+// so every pooled connection uses it, for both URL and keyword/value DSNs.
+// An empty value leaves the DSN untouched.
+func withRuntimeParam(dsn, key, value string) string {
+	if value == "" {
+		return dsn
+	}
+	if strings.Contains(dsn, "://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return dsn
+		}
+		query := u.Query()
+		query.Set(key, value)
+		u.RawQuery = query.Encode()
+		return u.String()
+	}
+	return dsn + " " + key + "=" + value
+}
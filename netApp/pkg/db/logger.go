@@ -0,0 +1,90 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"log/slog"
+	"time"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+var logLevels = map[string]gormlogger.LogLevel{
+	"silent": gormlogger.Silent,
+	"error":  gormlogger.Error,
+	"warn":   gormlogger.Warn,
+	"info":   gormlogger.Info,
+}
+
+type debugSQLKey struct{}
+
+// WithDebugSQL makes the query logger log every statement for ctx,
+// regardless of the configured level.
+func WithDebugSQL(ctx context.Context) context.Context {
+	return context.WithValue(ctx, debugSQLKey{}, true)
+}
+
+func debugSQL(ctx context.Context) bool {
+	enabled, _ := ctx.Value(debugSQLKey{}).(bool)
+	return enabled
+}
+
+type queryLogger struct {
+	logger *slog.Logger
+	level  gormlogger.LogLevel
+}
+
+func newQueryLogger(logger *slog.Logger, level string) gormlogger.Interface {
+	gormLevel, ok := logLevels[level]
+	if !ok {
+		gormLevel = gormlogger.Warn
+	}
+	return &queryLogger{logger: logger, level: gormLevel}
+}
+
+func (l *queryLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return &queryLogger{logger: l.logger, level: level}
+}
+
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	level := l.level
+	if debugSQL(ctx) {
+		level = gormlogger.Info
+	}
+	if level <= gormlogger.Silent {
+		return
+	}
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && level >= gormlogger.Error:
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "query failed", "sql", sql, "rows", rows, "duration", elapsed, "error", err)
+	case elapsed > slowQueryThreshold && level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "duration", elapsed)
+	case level >= gormlogger.Info:
+		sql, rows := fc()
+		l.logger.InfoContext(ctx, "query", "sql", sql, "rows", rows, "duration", elapsed)
+	}
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"classroomWebGolang/pkg/db"
+	"classroomWebGolang/pkg/middleware"
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type routeKey struct{}
+
+type route struct {
+	pattern string
+}
+
+type Metrics struct {
+	Registry *prometheus.Registry
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		Registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Number of HTTP requests by route and status.",
+		}, []string{"route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Duration of HTTP requests by route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"route", "status"}),
+	}
+	m.Registry.MustRegister(
+		m.requests,
+		m.duration,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+func (m *Metrics) RegisterDb(database *db.Db) error {
+	sqlDb, err := database.DB.DB()
+	if err != nil {
+		return err
+	}
+	return m.Registry.Register(collectors.NewDBStatsCollector(sqlDb, database.Dialector.Name()))
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.Registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		matched := &route{}
+		wrapper := &middleware.WrapperWriter{
+			ResponseWriter: w,
+			StatusCode:     http.StatusOK,
+		}
+		next.ServeHTTP(wrapper, r.WithContext(context.WithValue(r.Context(), routeKey{}, matched)))
+		pattern := matched.pattern
+		if pattern == "" {
+			pattern = "unmatched"
+		}
+		status := strconv.Itoa(wrapper.StatusCode)
+		m.requests.WithLabelValues(pattern, status).Inc()
+		m.duration.WithLabelValues(pattern, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// Route records the pattern matched by a ServeMux so Middleware can label
+// requests by route instead of raw path. The innermost match wins.
+func Route(next *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r)
+		matched, ok := r.Context().Value(routeKey{}).(*route)
+		if ok && matched.pattern == "" {
+			matched.pattern = r.Pattern
+		}
+	})
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"classroomWebGolang/configs"
+	"classroomWebGolang/pkg/response"
+	"context"
+	"github.com/golang-jwt/jwt/v5"
+	"net/http"
+	"strings"
+)
+
+func NewAuth(conf configs.AuthConfig) func(http.Handler) http.Handler {
+	if conf.Method == configs.AuthMethodApiKey {
+		return ApiKey(conf.ApiKeys)
+	}
+	return Auth(conf.JwtSecret)
+}
+
+func Auth(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authHeader := r.Header.Get("Authorization")
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				response.JsonError(w, r, response.CodeMissingToken, http.StatusUnauthorized)
+				return
+			}
+			if secret == "" {
+				response.JsonError(w, r, response.CodeAuthNotConfigured, http.StatusUnauthorized)
+				return
+			}
+			claims := &jwt.RegisteredClaims{}
+			_, err := jwt.ParseWithClaims(strings.TrimPrefix(authHeader, "Bearer "), claims, func(token *jwt.Token) (any, error) {
+				return []byte(secret), nil
+			}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+			if err != nil {
+				response.JsonError(w, r, response.CodeInvalidToken, http.StatusUnauthorized, err.Error())
+				return
+			}
+			ctx := context.WithValue(r.Context(), ContextSubjectKey, claims.Subject)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package middleware
+
+import "net/http"
+
+// Chain wraps h so that the first middleware is the outermost one and sees
+// the request first.
+func Chain(h http.Handler, mw ...func(http.Handler) http.Handler) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i](h)
+	}
+	return h
+}
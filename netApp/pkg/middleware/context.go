@@ -0,0 +1,26 @@
+package middleware
+
+import "context"
+
+type key string
+
+const (
+	ContextRequestIDKey key = "ContextRequestIDKey"
+	ContextSubjectKey   key = "ContextSubjectKey"
+)
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, ok := ctx.Value(ContextRequestIDKey).(string)
+	if !ok {
+		return ""
+	}
+	return requestID
+}
+
+func SubjectFromContext(ctx context.Context) string {
+	subject, ok := ctx.Value(ContextSubjectKey).(string)
+	if !ok {
+		return ""
+	}
+	return subject
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/response"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+var corsAllowHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	RequestIDHeader,
+	ApiKeyHeader,
+	"Idempotency-Key",
+	"If-None-Match",
+	"If-Unmodified-Since",
+	DebugSQLHeader,
+}
+
+// corsExposeHeaders lists the response headers browser clients may read
+// beyond the CORS safelisted ones.
+var corsExposeHeaders = []string{
+	"Location",
+	"ETag",
+	"Last-Modified",
+	"X-Total-Count",
+	"X-Limit",
+	"X-Offset",
+	"X-Next-Cursor",
+	RequestIDHeader,
+	"Idempotent-Replayed",
+	"Deprecation",
+	"Link",
+	"Retry-After",
+}
+
+func Cors(origins []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin != "" && (slices.Contains(origins, origin) || slices.Contains(origins, "*")) {
+				header := w.Header()
+				header.Set("Access-Control-Allow-Origin", origin)
+				header.Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+				header.Set("Access-Control-Allow-Headers", strings.Join(corsAllowHeaders, ", "))
+				header.Set("Access-Control-Expose-Headers", strings.Join(corsExposeHeaders, ", "))
+				header.Add("Vary", "Origin")
+			}
+			if r.Method == http.MethodOptions {
+				response.NoContent(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/db"
+	"net/http"
+	"strconv"
+)
+
+const DebugSQLHeader = "X-Debug-SQL"
+
+// DebugSQL logs every query of a request that sends X-Debug-SQL: true.
+// Such requests must pass auth, since the log reveals query internals.
+func DebugSQL(auth func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		debug := auth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(db.WithDebugSQL(r.Context())))
+		}))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			enabled, _ := strconv.ParseBool(r.Header.Get(DebugSQLHeader))
+			if !enabled {
+				next.ServeHTTP(w, r)
+				return
+			}
+			debug.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package middleware
+
+import "net/http"
+
+func Deprecated(successorPrefix string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Link", "<"+successorPrefix+r.URL.Path+">; rel=\"successor-version\"")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+func Logging(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			wrapper := &WrapperWriter{
+				ResponseWriter: w,
+				StatusCode:     http.StatusOK,
+			}
+			next.ServeHTTP(wrapper, r)
+			logger.Info("request",
+				"request_id", RequestIDFromContext(r.Context()),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", wrapper.StatusCode,
+				"duration", time.Since(start),
+			)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/response"
+	"crypto/sha256"
+	"encoding/hex"
+	"golang.org/x/time/rate"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	rateLimitCleanupInterval = time.Minute
+	rateLimitIdleTimeout     = 3 * time.Minute
+)
+
+type rateLimitClient struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	clients map[string]*rateLimitClient
+	limit   rate.Limit
+	burst   int
+	// keys holds the SHA-256 of each configured API key, so only real keys
+	// get a bucket of their own.
+	keys map[string]bool
+}
+
+func RateLimit(rps float64, burst int, apiKeys []string) func(http.Handler) http.Handler {
+	limiter := &rateLimiter{
+		clients: make(map[string]*rateLimitClient),
+		limit:   rate.Limit(rps),
+		burst:   burst,
+		keys:    make(map[string]bool, len(apiKeys)),
+	}
+	for _, key := range apiKeys {
+		limiter.keys[hashApiKey(key)] = true
+	}
+	go limiter.cleanup()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			reservation := limiter.get(limiter.clientKey(r)).Reserve()
+			if delay := reservation.Delay(); !reservation.OK() || delay > 0 {
+				reservation.Cancel()
+				w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(max(delay, time.Second).Seconds()))))
+				response.JsonError(w, r, response.CodeRateLimited, http.StatusTooManyRequests)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (l *rateLimiter) get(key string) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	client, ok := l.clients[key]
+	if !ok {
+		client = &rateLimitClient{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.clients[key] = client
+	}
+	client.lastSeen = time.Now()
+	return client.limiter
+}
+
+func (l *rateLimiter) cleanup() {
+	ticker := time.NewTicker(rateLimitCleanupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.mu.Lock()
+		for key, client := range l.clients {
+			if time.Since(client.lastSeen) > rateLimitIdleTimeout {
+				delete(l.clients, key)
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// clientKey buckets by IP unless the request carries a configured API key.
+// Unknown keys fall back to the IP, otherwise rotating bogus keys would get
+// a fresh bucket per request and grow the client map without bound.
+func (l *rateLimiter) clientKey(r *http.Request) string {
+	if key := r.Header.Get(ApiKeyHeader); key != "" {
+		if hash := hashApiKey(key); l.keys[hash] {
+			return "key:" + hash
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+func hashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/response"
+	"net/http"
+)
+
+// ReadOnly rejects every request with 503 while enabled. It is meant for
+// write routes only, so reads keep working during maintenance.
+func ReadOnly(enabled bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if !enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			response.JsonError(w, r, response.CodeMaintenance, http.StatusServiceUnavailable)
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/response"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// recoverWriter records whether the response has started, after which a
+// JSON error can no longer be sent.
+type recoverWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *recoverWriter) WriteHeader(statusCode int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recoverWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recoverWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Recover answers a panic with a JSON 500 while nothing has been written
+// yet. Once the response has started, it aborts the connection instead, so
+// the client sees a truncated response rather than a corrupted one.
+func Recover(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &recoverWriter{ResponseWriter: w}
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				logger.Error("panic while handling request",
+					"request_id", w.Header().Get(RequestIDHeader),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"error", fmt.Sprint(err),
+					"stack", string(debug.Stack()),
+					"response_started", rw.started,
+				)
+				if rw.started {
+					panic(http.ErrAbortHandler)
+				}
+				response.JsonError(w, r, response.CodeInternal, http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
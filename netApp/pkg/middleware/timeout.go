@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"classroomWebGolang/pkg/response"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Timeout answers with 503 once a handler runs longer than d. Requests whose
+// path ends with one of exempt are passed through untouched, because
+// http.TimeoutHandler buffers the response and cannot stream.
+func Timeout(d time.Duration, exempt ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, suffix := range exempt {
+				if strings.HasSuffix(r.URL.Path, suffix) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+			lang := response.Language(r)
+			body, _ := json.Marshal(response.ErrorResponse{
+				Error:  response.Message(lang, response.CodeTimeout),
+				Code:   response.CodeTimeout,
+				Status: http.StatusServiceUnavailable,
+			})
+			http.TimeoutHandler(next, d, string(body)+"\n").ServeHTTP(&timeoutWriter{ResponseWriter: w, lang: lang}, r)
+		})
+	}
+}
+
+// timeoutWriter labels the body http.TimeoutHandler writes on expiry, which
+// is the only 503 that arrives without a Content-Type.
+type timeoutWriter struct {
+	http.ResponseWriter
+	lang string
+}
+
+func (w *timeoutWriter) WriteHeader(status int) {
+	if status == http.StatusServiceUnavailable && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Language", w.lang)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *timeoutWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
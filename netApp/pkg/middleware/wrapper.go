@@ -0,0 +1,17 @@
+package middleware
+
+import "net/http"
+
+type WrapperWriter struct {
+	http.ResponseWriter
+	StatusCode int
+}
+
+func (w *WrapperWriter) WriteHeader(statusCode int) {
+	w.ResponseWriter.WriteHeader(statusCode)
+	w.StatusCode = statusCode
+}
+
+func (w *WrapperWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,49 @@
+package phone
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var formats = map[string]*regexp.Regexp{
+	"ANY":  regexp.MustCompile(`^\+?[\d\s().-]{5,20}$`),
+	"E164": regexp.MustCompile(`^\+[1-9]\d{1,14}$`),
+	"US":   regexp.MustCompile(`^(\+1[\s.-]?)?\(?\d{3}\)?[\s.-]?\d{3}[\s.-]?\d{4}$`),
+	"RU":   regexp.MustCompile(`^(\+7|8)[\s-]?\(?\d{3}\)?[\s-]?\d{3}[\s-]?\d{2}[\s-]?\d{2}$`),
+}
+
+func IsSupportedFormat(format string) bool {
+	_, ok := formats[format]
+	return ok
+}
+
+func Validate(number, format string) error {
+	pattern, ok := formats[format]
+	if !ok {
+		return fmt.Errorf("unsupported phone format: %q", format)
+	}
+	if !pattern.MatchString(number) {
+		return fmt.Errorf("invalid phone_number: %q does not match %s format", number, format)
+	}
+	return nil
+}
+
+// Normalize strips spaces, dashes, dots and parentheses so the same number
+// is always stored the same way, e.g. "+1 (555) 123-4567" becomes
+// "+15551234567". A leading plus is kept, everything else but digits is
+// dropped.
+func Normalize(number string) string {
+	number = strings.TrimSpace(number)
+	var b strings.Builder
+	b.Grow(len(number))
+	if strings.HasPrefix(number, "+") {
+		b.WriteByte('+')
+	}
+	for _, c := range number {
+		if c >= '0' && c <= '9' {
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,27 @@
+package phone
+
+import "testing"
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name   string
+		number string
+		want   string
+	}{
+		{"already canonical", "+15551234567", "+15551234567"},
+		{"spaces", "+7 999 000 11 22", "+79990001122"},
+		{"dashes", "555-123-4567", "5551234567"},
+		{"parentheses", "+1 (555) 123-4567", "+15551234567"},
+		{"dots", "555.123.4567", "5551234567"},
+		{"surrounding space", "  +44 20 7946 0958 ", "+442079460958"},
+		{"plus only at start", "8 (999) +000-11-22", "89990001122"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.number); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.number, got, tt.want)
+			}
+		})
+	}
+}
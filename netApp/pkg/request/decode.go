@@ -5,9 +5,13 @@ import (
 	"io"
 )
 
+// Decode rejects fields T does not declare, so a typo such as "nmae" fails
+// instead of being dropped silently.
 func Decode[T any](body io.Reader) (T, error) {
 	var payload T
-	err := json.NewDecoder(body).Decode(&payload)
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&payload)
 	if err != nil {
 		return payload, err
 	}
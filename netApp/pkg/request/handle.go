@@ -2,19 +2,47 @@ package request
 
 import (
 	"classroomWebGolang/pkg/response"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strings"
 )
 
 func HandleBody[T any](w *http.ResponseWriter, r *http.Request) (*T, error) {
 	body, err := Decode[T](r.Body)
 	if err != nil {
-		response.Json(*w, err.Error(), http.StatusBadRequest)
+		WriteDecodeError(*w, r, err)
 		return nil, err
 	}
 	err = IsValid[T](body)
 	if err != nil {
-		response.Json(*w, err.Error(), http.StatusBadRequest)
+		response.JsonError(*w, r, response.CodeValidationFailed, http.StatusBadRequest)
 		return nil, err
 	}
 	return &body, nil
 }
+
+func WriteDecodeError(w http.ResponseWriter, r *http.Request, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		response.JsonError(w, r, response.CodeBodyTooLarge, http.StatusRequestEntityTooLarge, maxBytesErr.Limit)
+		return
+	}
+	// encoding/json has no error type for unknown fields, only this message.
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		response.JsonError(w, r, response.CodeUnknownField, http.StatusBadRequest, field)
+		return
+	}
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		response.JsonError(w, r, response.CodeMalformedJson, http.StatusBadRequest)
+		return
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		response.JsonError(w, r, response.CodeInvalidType, http.StatusBadRequest, typeErr.Field)
+		return
+	}
+	response.JsonError(w, r, response.CodeInvalidBody, http.StatusBadRequest)
+}
@@ -0,0 +1,230 @@
+package response
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Code identifies an error message independently of its language. Codes are
+// part of the API and must not be renamed once released.
+type Code string
+
+const (
+	CodeInternal              Code = "internal_error"
+	CodeBodyTooLarge          Code = "body_too_large"
+	CodeFileTooLarge          Code = "file_too_large"
+	CodeUnsupportedMedia      Code = "unsupported_media_type"
+	CodeRateLimited           Code = "rate_limited"
+	CodeMissingToken          Code = "missing_token"
+	CodeInvalidToken          Code = "invalid_token"
+	CodeAuthNotConfigured     Code = "auth_not_configured"
+	CodeMissingApiKey         Code = "missing_api_key"
+	CodeInvalidApiKey         Code = "invalid_api_key"
+	CodePhoneInUse            Code = "phone_in_use"
+	CodeEmptyBatch            Code = "empty_batch"
+	CodeBatchTooLarge         Code = "batch_too_large"
+	CodeEmptyPatch            Code = "empty_patch"
+	CodeEmptyIds              Code = "empty_ids"
+	CodeTooManyIds            Code = "too_many_ids"
+	CodeCursorConflict        Code = "cursor_conflict"
+	CodeTimeout               Code = "request_timeout"
+	CodeIdempotencyKeyTooLong Code = "idempotency_key_too_long"
+	CodeIdempotencyKeyReused  Code = "idempotency_key_reused"
+	CodeTooManyMatches        Code = "too_many_matches"
+	CodeValidationFailed      Code = "validation_failed"
+	CodeUnknownField          Code = "unknown_field"
+	CodeMaintenance           Code = "maintenance"
+	CodeNotFound              Code = "not_found"
+	CodeRecordModified        Code = "record_modified"
+	CodeRecordNotDeleted      Code = "record_not_deleted"
+	CodeInvalidId             Code = "invalid_id"
+	CodeInvalidInteger        Code = "invalid_integer"
+	CodeInvalidBoolean        Code = "invalid_boolean"
+	CodeInvalidTimestamp      Code = "invalid_timestamp"
+	CodeInvalidRange          Code = "invalid_range"
+	CodeInvalidChoice         Code = "invalid_choice"
+	CodeInvalidCount          Code = "invalid_count"
+	CodeInvalidField          Code = "invalid_field"
+	CodeInvalidSort           Code = "invalid_sort"
+	CodeInvalidCursor         Code = "invalid_cursor"
+	CodeEmptyFilter           Code = "empty_filter"
+	CodeImmutableField        Code = "immutable_field"
+	CodeMissingFile           Code = "missing_file"
+	CodeInvalidCsv            Code = "invalid_csv"
+	CodeMissingColumn         Code = "missing_column"
+	CodeMalformedJson         Code = "malformed_json"
+	CodeInvalidBody           Code = "invalid_body"
+	CodeInvalidType           Code = "invalid_type"
+	CodeUnavailable           Code = "unavailable"
+)
+
+const defaultLanguage = "en"
+
+var messages = map[string]map[Code]string{
+	"en": {
+		CodeInternal:              "internal server error",
+		CodeBodyTooLarge:          "request body exceeds limit of %d bytes",
+		CodeFileTooLarge:          "file exceeds limit of %d bytes",
+		CodeUnsupportedMedia:      "Content-Type must be application/json",
+		CodeRateLimited:           "rate limit exceeded",
+		CodeMissingToken:          "missing bearer token",
+		CodeInvalidToken:          "invalid token: %s",
+		CodeAuthNotConfigured:     "authentication is not configured",
+		CodeMissingApiKey:         "missing api key",
+		CodeInvalidApiKey:         "invalid api key",
+		CodePhoneInUse:            "phone number is already in use",
+		CodeEmptyBatch:            "request body must contain at least one record",
+		CodeBatchTooLarge:         "batch size %d exceeds limit of %d",
+		CodeEmptyPatch:            "request body must contain at least one field",
+		CodeEmptyIds:              "ids must contain at least one id",
+		CodeTooManyIds:            "ids count %d exceeds limit of %d",
+		CodeCursorConflict:        "after cannot be combined with sort or offset",
+		CodeTimeout:               "request timed out",
+		CodeIdempotencyKeyTooLong: "Idempotency-Key must be at most %d characters",
+		CodeIdempotencyKeyReused:  "Idempotency-Key was already used with a different request body",
+		CodeTooManyMatches:        "filter matches %d records, limit is %d",
+		CodeValidationFailed:      "validation failed",
+		CodeUnknownField:          "unknown field %s",
+		CodeMaintenance:           "service is in read-only maintenance mode",
+		CodeNotFound:              "record not found",
+		CodeRecordModified:        "record has been modified",
+		CodeRecordNotDeleted:      "record is not deleted",
+		CodeInvalidId:             "invalid id %q",
+		CodeInvalidInteger:        "invalid %s: %q is not an integer",
+		CodeInvalidBoolean:        "invalid %s: %q is not a boolean",
+		CodeInvalidTimestamp:      "invalid %s: %q is not an RFC3339 timestamp",
+		CodeInvalidRange:          "invalid range: %s must not be greater than %s",
+		CodeInvalidChoice:         "invalid %s: %q, must be one of %s",
+		CodeInvalidCount:          "invalid count: %q is not a positive integer",
+		CodeInvalidField:          "invalid field: %q",
+		CodeInvalidSort:           "invalid sort field: %q",
+		CodeInvalidCursor:         "invalid cursor",
+		CodeEmptyFilter:           "filter must set at least one of name, min_age or max_age",
+		CodeImmutableField:        "updates cannot set %s",
+		CodeMissingFile:           "request must be a multipart form with a file field",
+		CodeInvalidCsv:            "failed to read csv header",
+		CodeMissingColumn:         "csv header is missing column %q",
+		CodeMalformedJson:         "request body is not valid JSON",
+		CodeInvalidBody:           "request body has an invalid structure",
+		CodeInvalidType:           "invalid type for %s",
+		CodeUnavailable:           "database is unavailable",
+	},
+	"ru": {
+		CodeInternal:              "внутренняя ошибка сервера",
+		CodeBodyTooLarge:          "тело запроса превышает лимит в %d байт",
+		CodeFileTooLarge:          "файл превышает лимит в %d байт",
+		CodeUnsupportedMedia:      "Content-Type должен быть application/json",
+		CodeRateLimited:           "превышен лимит запросов",
+		CodeMissingToken:          "отсутствует bearer-токен",
+		CodeInvalidToken:          "недействительный токен: %s",
+		CodeAuthNotConfigured:     "аутентификация не настроена",
+		CodeMissingApiKey:         "отсутствует api-ключ",
+		CodeInvalidApiKey:         "недействительный api-ключ",
+		CodePhoneInUse:            "номер телефона уже используется",
+		CodeEmptyBatch:            "тело запроса должно содержать хотя бы одну запись",
+		CodeBatchTooLarge:         "размер пакета %d превышает лимит %d",
+		CodeEmptyPatch:            "тело запроса должно содержать хотя бы одно поле",
+		CodeEmptyIds:              "ids должен содержать хотя бы один идентификатор",
+		CodeTooManyIds:            "количество ids %d превышает лимит %d",
+		CodeCursorConflict:        "after нельзя совмещать с sort или offset",
+		CodeTimeout:               "превышено время обработки запроса",
+		CodeIdempotencyKeyTooLong: "Idempotency-Key должен быть не длиннее %d символов",
+		CodeIdempotencyKeyReused:  "Idempotency-Key уже использован с другим телом запроса",
+		CodeTooManyMatches:        "фильтру соответствует %d записей, лимит %d",
+		CodeValidationFailed:      "ошибка валидации",
+		CodeUnknownField:          "неизвестное поле %s",
+		CodeMaintenance:           "сервис в режиме обслуживания, запись недоступна",
+		CodeNotFound:              "запись не найдена",
+		CodeRecordModified:        "запись была изменена",
+		CodeRecordNotDeleted:      "запись не удалена",
+		CodeInvalidId:             "недопустимый id %q",
+		CodeInvalidInteger:        "недопустимый %s: %q не является целым числом",
+		CodeInvalidBoolean:        "недопустимый %s: %q не является логическим значением",
+		CodeInvalidTimestamp:      "недопустимый %s: %q не является меткой времени RFC3339",
+		CodeInvalidRange:          "недопустимый диапазон: %s не должен быть больше %s",
+		CodeInvalidChoice:         "недопустимый %s: %q, допустимые значения: %s",
+		CodeInvalidCount:          "недопустимый count: %q не является положительным целым числом",
+		CodeInvalidField:          "недопустимое поле: %q",
+		CodeInvalidSort:           "недопустимое поле сортировки: %q",
+		CodeInvalidCursor:         "недопустимый курсор",
+		CodeEmptyFilter:           "фильтр должен задавать хотя бы одно из name, min_age или max_age",
+		CodeImmutableField:        "updates не может изменять %s",
+		CodeMissingFile:           "запрос должен быть multipart-формой с полем file",
+		CodeInvalidCsv:            "не удалось прочитать заголовок csv",
+		CodeMissingColumn:         "в заголовке csv отсутствует столбец %q",
+		CodeMalformedJson:         "тело запроса не является корректным JSON",
+		CodeInvalidBody:           "тело запроса имеет недопустимую структуру",
+		CodeInvalidType:           "недопустимый тип поля %s",
+		CodeUnavailable:           "база данных недоступна",
+	},
+}
+
+// JsonError writes the catalog message for code in the language negotiated
+// from the request's Accept-Language header.
+func JsonError(w http.ResponseWriter, r *http.Request, code Code, status int, args ...any) {
+	lang := Language(r)
+	w.Header().Set("Content-Language", lang)
+	w.Header().Add("Vary", "Accept-Language")
+	_ = Json(w, ErrorResponse{Error: Message(lang, code, args...), Code: code, Status: status}, status)
+}
+
+// CodedError carries a catalog code out of code that does not write the
+// response itself, such as parameter parsing. Error renders it in English.
+type CodedError struct {
+	Code Code
+	Args []any
+}
+
+func NewError(code Code, args ...any) error {
+	return &CodedError{Code: code, Args: args}
+}
+
+func (e *CodedError) Error() string {
+	return Message(defaultLanguage, e.Code, e.Args...)
+}
+
+// Message falls back to English when the language or code is not in the
+// catalog, and to the code itself as a last resort.
+func Message(lang string, code Code, args ...any) string {
+	format, ok := messages[lang][code]
+	if !ok {
+		format, ok = messages[defaultLanguage][code]
+	}
+	if !ok {
+		return string(code)
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// Language picks the supported language with the highest q-value in the
+// Accept-Language header. Region subtags are ignored, so "ru-RU" selects "ru".
+func Language(r *http.Request) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		base, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if _, ok := messages[base]; ok && q > 0 {
+			candidates = append(candidates, candidate{lang: base, q: q})
+		}
+	}
+	if len(candidates) == 0 {
+		return defaultLanguage
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].lang
+}
@@ -0,0 +1,49 @@
+package response
+
+import (
+	"encoding/xml"
+	"github.com/munnerz/goautoneg"
+	"net/http"
+)
+
+func Xml(w http.ResponseWriter, data any, status int) error {
+	body, err := xml.Marshal(data)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	_, err = w.Write(append([]byte(xml.Header), append(body, '\n')...))
+	return err
+}
+
+func Write(w http.ResponseWriter, r *http.Request, data any, status int) error {
+	w.Header().Add("Vary", "Accept")
+	if MediaType(r) == "application/xml" {
+		return Xml(w, data, status)
+	}
+	return Json(w, data, status)
+}
+
+// MediaType is the representation Write picks for r, either
+// application/json or application/xml.
+func MediaType(r *http.Request) string {
+	if wantsXml(r.Header.Get("Accept")) {
+		return "application/xml"
+	}
+	return "application/json"
+}
+
+// offeredTypes lists JSON first, so it wins ties and wildcards.
+var offeredTypes = []string{"application/json", "application/xml", "text/xml"}
+
+// wantsXml honours q-values, so "application/json;q=0.5, application/xml"
+// picks XML. A missing or unmatched Accept header means JSON.
+func wantsXml(accept string) bool {
+	switch goautoneg.Negotiate(accept, offeredTypes) {
+	case "application/xml", "text/xml":
+		return true
+	}
+	return false
+}
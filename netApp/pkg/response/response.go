@@ -2,15 +2,31 @@ package response
 
 import (
 	"encoding/json"
-	"log"
 	"net/http"
 )
 
-func Json(w http.ResponseWriter, data any, status int) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	err := json.NewEncoder(w).Encode(data)
+func Json(w http.ResponseWriter, data any, status int) error {
+	body, err := json.Marshal(data)
 	if err != nil {
-		log.Fatalf("Error while encoding response: %v", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
 	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, err = w.Write(append(body, '\n'))
+	return err
+}
+
+type ErrorResponse struct {
+	Error  string `json:"error"`
+	Code   Code   `json:"code,omitempty"`
+	Status int    `json:"status"`
+}
+
+// NoContent writes a bodiless 204, dropping any Content-Type a middleware
+// may have set in advance.
+func NoContent(w http.ResponseWriter) {
+	w.Header().Del("Content-Type")
+	w.Header().Del("Content-Length")
+	w.WriteHeader(http.StatusNoContent)
 }
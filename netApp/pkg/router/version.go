@@ -0,0 +1,19 @@
+package router
+
+import "net/http"
+
+// Mount serves handler under prefix, stripping it before routing. An empty
+// prefix serves handler as is.
+func Mount(prefix string, handler http.Handler) http.Handler {
+	if prefix == "" {
+		return handler
+	}
+	mux := http.NewServeMux()
+	mux.Handle(prefix+"/", http.StripPrefix(prefix, handler))
+	return mux
+}
+
+func Version(router *http.ServeMux, version string, handler http.Handler) {
+	prefix := "/" + version
+	router.Handle(prefix+"/", http.StripPrefix(prefix, handler))
+}
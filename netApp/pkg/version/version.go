@@ -0,0 +1,28 @@
+package version
+
+import "runtime"
+
+// Set at build time, e.g.
+//
+//	go build -ldflags "-X classroomWebGolang/pkg/version.Commit=$(git rev-parse --short HEAD)"
+var (
+	Commit    = "dev"
+	BuildTime = "dev"
+	GoVersion = ""
+)
+
+type Info struct {
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// Get falls back to the running toolchain's version when GoVersion was not
+// injected.
+func Get() Info {
+	goVersion := GoVersion
+	if goVersion == "" {
+		goVersion = runtime.Version()
+	}
+	return Info{Commit: Commit, BuildTime: BuildTime, GoVersion: goVersion}
+}